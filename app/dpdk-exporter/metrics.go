@@ -1,12 +1,9 @@
 package main
 
-// #include <rte_ring.h>
-import "C"
-
 import (
 	"context"
 	"log"
-	"strings"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -14,6 +11,8 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/yerden/go-dpdk/eal"
 	"github.com/yerden/go-dpdk/ethdev"
+	"github.com/yerden/go-dpdk/malloc"
+	"github.com/yerden/go-dpdk/mempool"
 	"github.com/yerden/go-dpdk/memzone"
 	"github.com/yerden/go-dpdk/ring"
 )
@@ -21,19 +20,29 @@ import (
 const (
 	portNameLbl  = "port_name"
 	memzoneLbl   = "memzone_name"
+	ringLbl      = "ring_name"
+	prodSyncLbl  = "prod_sync_type"
+	consSyncLbl  = "cons_sync_type"
+	mempoolLbl   = "mempool_name"
+	opsNameLbl   = "ops_name"
+	statNameLbl  = "stat_name"
 	macAddrLbl   = "mac_addr"
 	drvNameLbl   = "driver_name"
 	ifaceNameLbl = "interface_name"
 	xstatNameLbl = "xstat_name"
+	socketLbl    = "socket_id"
+	pageSizeLbl  = "page_size"
 )
 const (
-	namespace    = "dpdk_exporter"
-	ringMzPrefix = "RG_"
+	namespace = "dpdk_exporter"
 )
 
 type Metrics struct {
-	EthDev *EthDevMetrics
-	Ring   *RingMetrics
+	EthDev  *EthDevMetrics
+	Ring    *RingMetrics
+	Mempool *MempoolMetrics
+	Memzone *MemzoneMetrics
+	Malloc  *MallocMetrics
 }
 
 func NewMetrics() (m *Metrics, err error) {
@@ -43,8 +52,11 @@ func NewMetrics() (m *Metrics, err error) {
 	}
 
 	m = &Metrics{
-		EthDev: ethDev,
-		Ring:   NewRingMetrics(),
+		EthDev:  ethDev,
+		Ring:    NewRingMetrics(),
+		Mempool: NewMempoolMetrics(),
+		Memzone: NewMemzoneMetrics(),
+		Malloc:  NewMallocMetrics(),
 	}
 	return
 }
@@ -56,6 +68,15 @@ func (m *Metrics) Collect() {
 	if err := m.Ring.Collect(); err != nil {
 		log.Printf("collect ring metrics: %v", err)
 	}
+	if err := m.Mempool.Collect(); err != nil {
+		log.Printf("collect mempool metrics: %v", err)
+	}
+	if err := m.Memzone.Collect(); err != nil {
+		log.Printf("collect memzone metrics: %v", err)
+	}
+	if err := m.Malloc.Collect(); err != nil {
+		log.Printf("collect malloc metrics: %v", err)
+	}
 }
 
 func (m *Metrics) StartCollecting(ctx context.Context) {
@@ -310,14 +331,16 @@ type RingMetrics struct {
 	MemzoneLen      *prometheus.GaugeVec
 	MemzonePageSize *prometheus.GaugeVec
 	MemzoneSocketID *prometheus.GaugeVec
+	Size            *prometheus.GaugeVec
 	Cap             *prometheus.GaugeVec
 	Count           *prometheus.GaugeVec
+	Info            *prometheus.GaugeVec
 }
 
 func NewRingMetrics() *RingMetrics {
 	var m RingMetrics
 
-	labelNames := []string{memzoneLbl}
+	labelNames := []string{ringLbl, memzoneLbl}
 	const subsystem = "ring"
 	m.MemzoneLen = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: namespace,
@@ -334,6 +357,11 @@ func NewRingMetrics() *RingMetrics {
 		Subsystem: subsystem,
 		Name:      "memzone_socket_id",
 	}, labelNames)
+	m.Size = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "size",
+	}, labelNames)
 	m.Cap = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: namespace,
 		Subsystem: subsystem,
@@ -344,26 +372,364 @@ func NewRingMetrics() *RingMetrics {
 		Subsystem: subsystem,
 		Name:      "count",
 	}, labelNames)
+	m.Info = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "info",
+	}, append(labelNames, prodSyncLbl, consSyncLbl))
 
 	return &m
 }
 
 func (m *RingMetrics) Collect() error {
-	memzone.Walk(func(mz *memzone.Memzone) {
-		name := mz.Name()
-		if !strings.HasPrefix(name, C.RTE_RING_MZ_PREFIX) {
+	// rings may be freed, don't export stale ones
+	m.MemzoneLen.Reset()
+	m.MemzonePageSize.Reset()
+	m.MemzoneSocketID.Reset()
+	m.Size.Reset()
+	m.Cap.Reset()
+	m.Count.Reset()
+	m.Info.Reset()
+
+	ring.Walk(func(r *ring.Ring) {
+		info := r.Info()
+		labels := prometheus.Labels{ringLbl: info.Name}
+
+		if mz := info.Memzone; mz != nil {
+			labels[memzoneLbl] = mz.Name()
+			m.MemzoneLen.With(labels).Set(float64(mz.Len()))
+			m.MemzonePageSize.With(labels).Set(float64(mz.HugePageSz()))
+			m.MemzoneSocketID.With(labels).Set(float64(mz.SocketID()))
+		} else {
+			labels[memzoneLbl] = ""
+		}
+
+		m.Size.With(labels).Set(float64(info.Size))
+		m.Cap.With(labels).Set(float64(info.Cap))
+		m.Count.With(labels).Set(float64(info.Count))
+
+		m.Info.With(prometheus.Labels{
+			ringLbl:     info.Name,
+			memzoneLbl:  labels[memzoneLbl],
+			prodSyncLbl: info.ProdSyncType.String(),
+			consSyncLbl: info.ConsSyncType.String(),
+		}).Set(1)
+	})
+	return nil
+}
+
+type MempoolMetrics struct {
+	Size        *prometheus.GaugeVec
+	EltSize     *prometheus.GaugeVec
+	CacheSize   *prometheus.GaugeVec
+	AvailCount  *prometheus.GaugeVec
+	InUseCount  *prometheus.GaugeVec
+	CachedCount *prometheus.GaugeVec
+	MemLen      *prometheus.GaugeVec
+	Info        *prometheus.GaugeVec
+	Stats       *prometheus.CounterVec
+
+	// previous values of statistics per mempool
+	prev map[string]mempool.Stats
+}
+
+func NewMempoolMetrics() *MempoolMetrics {
+	m := MempoolMetrics{prev: make(map[string]mempool.Stats)}
+
+	labelNames := []string{mempoolLbl}
+	const subsystem = "mempool"
+	m.Size = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "size",
+	}, labelNames)
+	m.EltSize = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "elt_size",
+	}, labelNames)
+	m.CacheSize = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "cache_size",
+	}, labelNames)
+	m.AvailCount = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "avail_count",
+	}, labelNames)
+	m.InUseCount = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "in_use_count",
+	}, labelNames)
+	m.CachedCount = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "cached_count",
+		Help:      "Number of objects in per-lcore caches",
+	}, labelNames)
+	m.MemLen = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "mem_len",
+		Help:      "Total length of memory chunks of the mempool",
+	}, labelNames)
+	m.Info = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "info",
+	}, append(labelNames, opsNameLbl))
+	m.Stats = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "stats",
+		Help:      "Mempool statistic counters, requires RTE_LIBRTE_MEMPOOL_STATS",
+	}, append(labelNames, statNameLbl))
+
+	return &m
+}
+
+func (m *MempoolMetrics) Collect() error {
+	// mempools may be freed, don't export stale ones
+	m.Size.Reset()
+	m.EltSize.Reset()
+	m.CacheSize.Reset()
+	m.AvailCount.Reset()
+	m.InUseCount.Reset()
+	m.CachedCount.Reset()
+	m.MemLen.Reset()
+	m.Info.Reset()
+
+	seen := make(map[string]struct{})
+	defer m.prune(seen)
+
+	mempool.Walk(func(mp *mempool.Mempool) {
+		name := mp.Name()
+		labels := prometheus.Labels{mempoolLbl: name}
+		seen[name] = struct{}{}
+
+		m.Size.With(labels).Set(float64(mp.Size()))
+		m.EltSize.With(labels).Set(float64(mp.EltSize()))
+		m.CacheSize.With(labels).Set(float64(mp.CacheSize()))
+		m.AvailCount.With(labels).Set(float64(mp.AvailCount()))
+		m.InUseCount.With(labels).Set(float64(mp.InUseCount()))
+		m.CachedCount.With(labels).Set(float64(mp.CachedCount()))
+
+		var memLen uintptr
+		mp.MemIter(func(c mempool.MemChunk) {
+			memLen += c.Len
+		})
+		m.MemLen.With(labels).Set(float64(memLen))
+
+		m.Info.With(prometheus.Labels{
+			mempoolLbl: name,
+			opsNameLbl: mp.OpsName(),
+		}).Set(1)
+
+		stats, err := mp.Stats()
+		if err != nil {
+			// simply not exporting
 			return
 		}
+		m.collectStats(name, stats)
+	})
+	return nil
+}
 
-		labels := prometheus.Labels{memzoneLbl: name}
+// prune removes statistics of mempools which are not seen anymore.
+func (m *MempoolMetrics) prune(seen map[string]struct{}) {
+	for name := range m.prev {
+		if _, ok := seen[name]; !ok {
+			delete(m.prev, name)
+			m.Stats.DeletePartialMatch(prometheus.Labels{mempoolLbl: name})
+		}
+	}
+}
 
-		m.MemzoneLen.With(labels).Set(float64(mz.Len()))
-		m.MemzonePageSize.With(labels).Set(float64(mz.HugePageSz()))
-		m.MemzoneSocketID.With(labels).Set(float64(mz.SocketID()))
+func (m *MempoolMetrics) collectStats(name string, next mempool.Stats) {
+	prev := m.prev[name]
+	m.prev[name] = next
+
+	for _, s := range []struct {
+		stat       string
+		prev, next uint64
+	}{
+		{"put_bulk", prev.PutBulk, next.PutBulk},
+		{"put_objs", prev.PutObjs, next.PutObjs},
+		{"put_common_pool_bulk", prev.PutCommonPoolBulk, next.PutCommonPoolBulk},
+		{"put_common_pool_objs", prev.PutCommonPoolObjs, next.PutCommonPoolObjs},
+		{"get_common_pool_bulk", prev.GetCommonPoolBulk, next.GetCommonPoolBulk},
+		{"get_common_pool_objs", prev.GetCommonPoolObjs, next.GetCommonPoolObjs},
+		{"get_success_bulk", prev.GetSuccessBulk, next.GetSuccessBulk},
+		{"get_success_objs", prev.GetSuccessObjs, next.GetSuccessObjs},
+		{"get_fail_bulk", prev.GetFailBulk, next.GetFailBulk},
+		{"get_fail_objs", prev.GetFailObjs, next.GetFailObjs},
+		{"get_success_blks", prev.GetSuccessBlks, next.GetSuccessBlks},
+		{"get_fail_blks", prev.GetFailBlks, next.GetFailBlks},
+	} {
+		if s.next > s.prev {
+			m.Stats.WithLabelValues(name, s.stat).Add(float64(s.next - s.prev))
+		}
+	}
+}
+
+type MemzoneMetrics struct {
+	Len           *prometheus.GaugeVec
+	PageSize      *prometheus.GaugeVec
+	SocketID      *prometheus.GaugeVec
+	SocketCount   *prometheus.GaugeVec
+	SocketLen     *prometheus.GaugeVec
+	PageSizeCount *prometheus.GaugeVec
+	PageSizeLen   *prometheus.GaugeVec
+}
+
+func NewMemzoneMetrics() *MemzoneMetrics {
+	var m MemzoneMetrics
+
+	labelNames := []string{memzoneLbl}
+	const subsystem = "memzone"
+	m.Len = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "len",
+	}, labelNames)
+	m.PageSize = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "page_size",
+	}, labelNames)
+	m.SocketID = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "socket_id",
+	}, labelNames)
+	m.SocketCount = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "socket_count",
+		Help:      "Number of memzones reserved on NUMA socket",
+	}, []string{socketLbl})
+	m.SocketLen = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "socket_len",
+		Help:      "Total length of memzones reserved on NUMA socket",
+	}, []string{socketLbl})
+	m.PageSizeCount = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "page_size_count",
+		Help:      "Number of memzones reserved with page size",
+	}, []string{pageSizeLbl})
+	m.PageSizeLen = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "page_size_len",
+		Help:      "Total length of memzones reserved with page size",
+	}, []string{pageSizeLbl})
 
-		r := (*ring.Ring)(mz.Addr())
-		m.Cap.With(labels).Set(float64(r.Cap()))
-		m.Count.With(labels).Set(float64(r.Count()))
+	return &m
+}
+
+func (m *MemzoneMetrics) Collect() error {
+	// memzones may be freed, don't export stale ones
+	m.Len.Reset()
+	m.PageSize.Reset()
+	m.SocketID.Reset()
+
+	memzone.Walk(func(mz *memzone.Memzone) {
+		labels := prometheus.Labels{memzoneLbl: mz.Name()}
+		m.Len.With(labels).Set(float64(mz.Len()))
+		m.PageSize.With(labels).Set(float64(mz.HugePageSz()))
+		m.SocketID.With(labels).Set(float64(mz.SocketID()))
 	})
+
+	stats := memzone.GetStats()
+	m.SocketCount.Reset()
+	m.SocketLen.Reset()
+	for socket, u := range stats.BySocket {
+		labels := prometheus.Labels{socketLbl: strconv.Itoa(socket)}
+		m.SocketCount.With(labels).Set(float64(u.Count))
+		m.SocketLen.With(labels).Set(float64(u.Len))
+	}
+
+	m.PageSizeCount.Reset()
+	m.PageSizeLen.Reset()
+	for pageSize, u := range stats.ByPageSize {
+		labels := prometheus.Labels{pageSizeLbl: strconv.FormatUint(pageSize, 10)}
+		m.PageSizeCount.With(labels).Set(float64(u.Count))
+		m.PageSizeLen.With(labels).Set(float64(u.Len))
+	}
+
+	return nil
+}
+
+type MallocMetrics struct {
+	HeapTotalBytes   *prometheus.GaugeVec
+	HeapFreeBytes    *prometheus.GaugeVec
+	HeapAllocBytes   *prometheus.GaugeVec
+	GreatestFreeSize *prometheus.GaugeVec
+	FreeCount        *prometheus.GaugeVec
+	AllocCount       *prometheus.GaugeVec
+}
+
+func NewMallocMetrics() *MallocMetrics {
+	var m MallocMetrics
+
+	labelNames := []string{socketLbl}
+	const subsystem = "malloc"
+	m.HeapTotalBytes = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "heap_total_bytes",
+	}, labelNames)
+	m.HeapFreeBytes = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "heap_free_bytes",
+	}, labelNames)
+	m.HeapAllocBytes = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "heap_alloc_bytes",
+	}, labelNames)
+	m.GreatestFreeSize = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "greatest_free_size",
+		Help:      "Size in bytes of largest free block on heap",
+	}, labelNames)
+	m.FreeCount = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "free_count",
+		Help:      "Number of free elements on heap",
+	}, labelNames)
+	m.AllocCount = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "alloc_count",
+		Help:      "Number of allocated elements on heap",
+	}, labelNames)
+
+	return &m
+}
+
+func (m *MallocMetrics) Collect() error {
+	for _, socket := range eal.Sockets() {
+		s, err := malloc.GetSocketStats(int(socket))
+		if err != nil {
+			return err
+		}
+
+		labels := prometheus.Labels{socketLbl: strconv.FormatUint(uint64(socket), 10)}
+		m.HeapTotalBytes.With(labels).Set(float64(s.HeapTotalBytes))
+		m.HeapFreeBytes.With(labels).Set(float64(s.HeapFreeBytes))
+		m.HeapAllocBytes.With(labels).Set(float64(s.HeapAllocBytes))
+		m.GreatestFreeSize.With(labels).Set(float64(s.GreatestFreeSize))
+		m.FreeCount.With(labels).Set(float64(s.FreeCount))
+		m.AllocCount.With(labels).Set(float64(s.AllocCount))
+	}
 	return nil
 }
@@ -0,0 +1,103 @@
+package common
+
+/*
+#define _GNU_SOURCE
+#include <stdio.h>
+#include <stdlib.h>
+
+struct memstream {
+	FILE *fp;
+	char *buf;
+	size_t size;
+};
+
+static int memstream_open(struct memstream *s)
+{
+	s->buf = NULL;
+	s->size = 0;
+	s->fp = open_memstream(&s->buf, &s->size);
+	return s->fp == NULL ? -1 : 0;
+}
+
+static void memstream_close(struct memstream *s)
+{
+	if (s->fp != NULL)
+		fclose(s->fp);
+	free(s->buf);
+	free(s);
+}
+*/
+import "C"
+
+import (
+	"io"
+	"syscall"
+	"unsafe"
+)
+
+// MemStream is the in-memory C stream. It may be used to collect the
+// output of DPDK functions which dump to FILE, e.g. rte_mempool_dump.
+// MemStream should be closed after use.
+type MemStream struct {
+	s *C.struct_memstream
+}
+
+// NewMemStream opens new MemStream.
+func NewMemStream() (*MemStream, error) {
+	s := (*C.struct_memstream)(C.calloc(1, C.sizeof_struct_memstream))
+	if s == nil {
+		return nil, syscall.ENOMEM
+	}
+
+	if rc, err := C.memstream_open(s); rc != 0 {
+		C.free(unsafe.Pointer(s))
+		return nil, err
+	}
+
+	return &MemStream{s}, nil
+}
+
+// FILE returns the stream as pointer to C FILE.
+func (ms *MemStream) FILE() unsafe.Pointer {
+	return unsafe.Pointer(ms.s.fp)
+}
+
+// Bytes flushes the stream and returns its contents. The slice refers
+// to C memory and is valid until the next write into the stream or
+// Close.
+func (ms *MemStream) Bytes() []byte {
+	C.fflush(ms.s.fp)
+	if ms.s.size == 0 {
+		return nil
+	}
+	return unsafe.Slice((*byte)(unsafe.Pointer(ms.s.buf)), ms.s.size)
+}
+
+// WriteTo implements io.WriterTo interface. It writes the contents of
+// the stream into w.
+func (ms *MemStream) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(ms.Bytes())
+	return int64(n), err
+}
+
+// Close closes the stream and releases its memory.
+func (ms *MemStream) Close() error {
+	C.memstream_close(ms.s)
+	ms.s = nil
+	return nil
+}
+
+// DumpTo calls fn with the C stream (FILE *) and writes everything fn
+// has written to the stream into w. It is used to redirect DPDK dump
+// functions into Go writers.
+func DumpTo(w io.Writer, fn func(fp unsafe.Pointer)) error {
+	ms, err := NewMemStream()
+	if err != nil {
+		return err
+	}
+	defer ms.Close()
+
+	fn(ms.FILE())
+	_, err = ms.WriteTo(w)
+	return err
+}
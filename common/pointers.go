@@ -1,7 +1,9 @@
 package common
 
 import (
+	"fmt"
 	"reflect"
+	"syscall"
 	"unsafe"
 )
 
@@ -49,3 +51,34 @@ func Memset(p unsafe.Pointer, init byte, n uintptr) {
 		b[i] = init
 	}
 }
+
+// CheckNoPointers returns an error wrapping syscall.EINVAL if type T
+// may contain Go pointers, i.e. values of T can't be placed in C or
+// DPDK memory. T may consist of numbers, booleans, arrays and structs
+// thereof.
+func CheckNoPointers[T any]() error {
+	var v T
+	return checkNoPointers(reflect.TypeOf(&v).Elem())
+}
+
+func checkNoPointers(t reflect.Type) error {
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Uintptr, reflect.Float32, reflect.Float64,
+		reflect.Complex64, reflect.Complex128:
+		return nil
+	case reflect.Array:
+		return checkNoPointers(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if err := checkNoPointers(t.Field(i).Type); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("%w: type %v may contain pointers", syscall.EINVAL, t)
+}
@@ -1,6 +1,8 @@
 package common
 
 import (
+	"errors"
+	"syscall"
 	"testing"
 	"unsafe"
 )
@@ -112,3 +114,41 @@ func BenchmarkMemset511(b *testing.B) {
 func BenchmarkPlainInit511(b *testing.B) {
 	benchmarkPlainInitN(b, 511)
 }
+
+func TestCheckNoPointers(t *testing.T) {
+	type plain struct {
+		A uint32
+		B [4]struct{ C float64 }
+		D bool
+	}
+
+	type ptr struct {
+		A uint32
+		B [4]struct{ C *int }
+	}
+
+	for i, err := range []error{
+		CheckNoPointers[int](),
+		CheckNoPointers[[16]byte](),
+		CheckNoPointers[plain](),
+		CheckNoPointers[struct{}](),
+	} {
+		if err != nil {
+			t.Fatal(i, err)
+		}
+	}
+
+	for i, err := range []error{
+		CheckNoPointers[*int](),
+		CheckNoPointers[[]byte](),
+		CheckNoPointers[string](),
+		CheckNoPointers[ptr](),
+		CheckNoPointers[unsafe.Pointer](),
+		CheckNoPointers[map[int]int](),
+		CheckNoPointers[interface{}](),
+	} {
+		if !errors.Is(err, syscall.EINVAL) {
+			t.Fatal(i, err)
+		}
+	}
+}
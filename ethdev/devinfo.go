@@ -0,0 +1,408 @@
+package ethdev
+
+/*
+#include <stdlib.h>
+#include <string.h>
+
+#include <rte_config.h>
+#include <rte_ethdev.h>
+#include <rte_dev_info.h>
+
+static uint16_t dcb_tc_rxq_base(const struct rte_eth_dcb_info *info, int pool, int tc) {
+	return info->tc_queue.tc_rxq[pool][tc].base;
+}
+
+static uint16_t dcb_tc_rxq_nb(const struct rte_eth_dcb_info *info, int pool, int tc) {
+	return info->tc_queue.tc_rxq[pool][tc].nb_queue;
+}
+
+static uint16_t dcb_tc_txq_base(const struct rte_eth_dcb_info *info, int pool, int tc) {
+	return info->tc_queue.tc_txq[pool][tc].base;
+}
+
+static uint16_t dcb_tc_txq_nb(const struct rte_eth_dcb_info *info, int pool, int tc) {
+	return info->tc_queue.tc_txq[pool][tc].nb_queue;
+}
+*/
+import "C"
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/yerden/go-dpdk/common"
+)
+
+// SpeedCapa returns supported speeds bitmap (RTE_ETH_LINK_SPEED_*).
+func (info *DevInfo) SpeedCapa() uint32 {
+	return uint32(info.speed_capa)
+}
+
+// RxOffloadCapa returns all Rx offload capabilities including all
+// per-queue ones.
+func (info *DevInfo) RxOffloadCapa() uint64 {
+	return uint64(info.rx_offload_capa)
+}
+
+// TxOffloadCapa returns all Tx offload capabilities including all
+// per-queue ones.
+func (info *DevInfo) TxOffloadCapa() uint64 {
+	return uint64(info.tx_offload_capa)
+}
+
+// RxQueueOffloadCapa returns device per-queue Rx offload
+// capabilities.
+func (info *DevInfo) RxQueueOffloadCapa() uint64 {
+	return uint64(info.rx_queue_offload_capa)
+}
+
+// TxQueueOffloadCapa returns device per-queue Tx offload
+// capabilities.
+func (info *DevInfo) TxQueueOffloadCapa() uint64 {
+	return uint64(info.tx_queue_offload_capa)
+}
+
+// HashKeySize returns hash key size in bytes.
+func (info *DevInfo) HashKeySize() uint8 {
+	return uint8(info.hash_key_size)
+}
+
+// FlowTypeRssOffloads returns bit mask of RSS offloads, the bit
+// offset also means flow type.
+func (info *DevInfo) FlowTypeRssOffloads() uint64 {
+	return uint64(info.flow_type_rss_offloads)
+}
+
+// MaxMACAddrs returns maximum number of MAC addresses.
+func (info *DevInfo) MaxMACAddrs() uint32 {
+	return uint32(info.max_mac_addrs)
+}
+
+// MaxHashMACAddrs returns maximum number of hash MAC addresses for
+// MTA and UTA.
+func (info *DevInfo) MaxHashMACAddrs() uint32 {
+	return uint32(info.max_hash_mac_addrs)
+}
+
+// MaxVFs returns maximum number of VFs.
+func (info *DevInfo) MaxVFs() uint16 {
+	return uint16(info.max_vfs)
+}
+
+// MaxVMDqPools returns maximum number of VMDq pools.
+func (info *DevInfo) MaxVMDqPools() uint16 {
+	return uint16(info.max_vmdq_pools)
+}
+
+// MaxLROPktSize returns maximum LRO aggregated packet size.
+func (info *DevInfo) MaxLROPktSize() uint32 {
+	return uint32(info.max_lro_pkt_size)
+}
+
+// DescLim is the contextual information of the device's Rx/Tx
+// descriptors.
+type DescLim struct {
+	// Max allowed number of descriptors.
+	NbMax uint16
+	// Min allowed number of descriptors.
+	NbMin uint16
+	// Number of descriptors should be aligned to.
+	NbAlign uint16
+	// Max number of segments per whole packet.
+	NbSegMax uint16
+	// Max number of segments per one MTU.
+	NbMTUSegMax uint16
+}
+
+func descLim(lim *C.struct_rte_eth_desc_lim) DescLim {
+	return DescLim{
+		NbMax:       uint16(lim.nb_max),
+		NbMin:       uint16(lim.nb_min),
+		NbAlign:     uint16(lim.nb_align),
+		NbSegMax:    uint16(lim.nb_seg_max),
+		NbMTUSegMax: uint16(lim.nb_mtu_seg_max),
+	}
+}
+
+// RxDescLim returns Rx descriptors limits.
+func (info *DevInfo) RxDescLim() DescLim {
+	return descLim(&info.rx_desc_lim)
+}
+
+// TxDescLim returns Tx descriptors limits.
+func (info *DevInfo) TxDescLim() DescLim {
+	return descLim(&info.tx_desc_lim)
+}
+
+// SwitchInfo is the switch information of the device.
+type SwitchInfo struct {
+	// Switch name.
+	Name string
+	// Switch domain ID, ports in the same switch domain may be
+	// configured for traffic forwarding between them.
+	DomainID uint16
+	// Switch port ID.
+	PortID uint16
+}
+
+// SwitchInfo returns switch information of the device.
+func (info *DevInfo) SwitchInfo() SwitchInfo {
+	return SwitchInfo{
+		Name:     C.GoString(info.switch_info.name),
+		DomainID: uint16(info.switch_info.domain_id),
+		PortID:   uint16(info.switch_info.port_id),
+	}
+}
+
+// FwVersionGet retrieves the firmware version of a device.
+//
+// Returns:
+//
+//	(-ENOTSUP) if operation is not supported.
+//	(-ENODEV) if port_id invalid.
+//	(-EIO) if device is removed.
+//	(-ERANGE) if the driver keeps reporting larger size.
+func (pid Port) FwVersionGet() (string, error) {
+	buf := make([]C.char, 64)
+	get := func() C.int {
+		return C.rte_eth_dev_fw_version_get(C.ushort(pid), &buf[0], C.size_t(len(buf)))
+	}
+
+	n := get()
+	if n > 0 {
+		// buffer is too small, n is the size needed including the
+		// terminating '\0'; retry once
+		buf = make([]C.char, n)
+		n = get()
+	}
+
+	if n > 0 {
+		return "", syscall.ERANGE
+	}
+	return C.GoString(&buf[0]), errget(n)
+}
+
+// GetEEPROMLength retrieves the size of device EEPROM.
+//
+// Returns:
+//
+//	(-ENOTSUP) if hardware doesn't support.
+//	(-ENODEV) if port_id invalid.
+//	(-EIO) if device is removed.
+func (pid Port) GetEEPROMLength() (int, error) {
+	return common.IntOrErr(C.rte_eth_dev_get_eeprom_length(C.ushort(pid)))
+}
+
+func readEEPROM(offset, length uint32, fn func(*C.struct_rte_dev_eeprom_info) C.int) ([]byte, error) {
+	if length == 0 {
+		return nil, nil
+	}
+
+	data := C.malloc(C.size_t(length))
+	defer C.free(data)
+	C.memset(data, 0, C.size_t(length))
+
+	info := &C.struct_rte_dev_eeprom_info{
+		data:   data,
+		offset: C.uint32_t(offset),
+		length: C.uint32_t(length),
+	}
+
+	if err := errget(fn(info)); err != nil {
+		return nil, err
+	}
+
+	return C.GoBytes(data, C.int(length)), nil
+}
+
+// GetEEPROM retrieves length bytes of device EEPROM starting at
+// offset.
+//
+// Returns:
+//
+//	(-ENOTSUP) if hardware doesn't support.
+//	(-ENODEV) if port_id invalid.
+//	(-EIO) if device is removed.
+func (pid Port) GetEEPROM(offset, length uint32) ([]byte, error) {
+	return readEEPROM(offset, length, func(info *C.struct_rte_dev_eeprom_info) C.int {
+		return C.rte_eth_dev_get_eeprom(C.ushort(pid), info)
+	})
+}
+
+// ModuleType is the plugin module EEPROM specification.
+type ModuleType uint32
+
+// Module EEPROM specifications.
+const (
+	ModuleSFF8079 ModuleType = C.RTE_ETH_MODULE_SFF_8079
+	ModuleSFF8472 ModuleType = C.RTE_ETH_MODULE_SFF_8472
+	ModuleSFF8436 ModuleType = C.RTE_ETH_MODULE_SFF_8436
+	ModuleSFF8636 ModuleType = C.RTE_ETH_MODULE_SFF_8636
+)
+
+// ModuleInfo is the plugin module information.
+type ModuleInfo struct {
+	// Type of plugin module EEPROM.
+	Type ModuleType
+	// Length of plugin module EEPROM.
+	EEPROMLen uint32
+}
+
+// GetModuleInfo retrieves the type and size of plugin module EEPROM.
+//
+// Returns:
+//
+//	(-ENOTSUP) if hardware doesn't support.
+//	(-ENODEV) if port_id invalid.
+//	(-EIO) if device is removed.
+//	(-EINVAL) if bad parameter.
+func (pid Port) GetModuleInfo() (ModuleInfo, error) {
+	var info C.struct_rte_eth_dev_module_info
+	err := errget(C.rte_eth_dev_get_module_info(C.ushort(pid), &info))
+	return ModuleInfo{ModuleType(info._type), uint32(info.eeprom_len)}, err
+}
+
+// GetModuleEEPROM retrieves length bytes of plugin module EEPROM
+// starting at offset.
+//
+// Returns:
+//
+//	(-ENOTSUP) if hardware doesn't support.
+//	(-EINVAL) if bad parameter.
+//	(-ENODEV) if port_id invalid.
+//	(-EIO) if device is removed.
+func (pid Port) GetModuleEEPROM(offset, length uint32) ([]byte, error) {
+	return readEEPROM(offset, length, func(info *C.struct_rte_dev_eeprom_info) C.int {
+		return C.rte_eth_dev_get_module_eeprom(C.ushort(pid), info)
+	})
+}
+
+// Transceiver reads the whole plugin module EEPROM and parses it.
+// See ParseTransceiver.
+func (pid Port) Transceiver() (*Transceiver, error) {
+	info, err := pid.GetModuleInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := pid.GetModuleEEPROM(0, info.EEPROMLen)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseTransceiver(info.Type, data)
+}
+
+// RegInfo is the device registers dump.
+type RegInfo struct {
+	// Registers data.
+	Data []byte
+	// Start register offset.
+	Offset uint32
+	// Size of device register.
+	Width uint32
+	// Device version.
+	Version uint32
+}
+
+// GetRegInfo retrieves device registers.
+//
+// Returns:
+//
+//	(-ENOTSUP) if hardware doesn't support.
+//	(-EINVAL) if bad parameter.
+//	(-ENODEV) if port_id invalid.
+//	(-EIO) if device is removed.
+func (pid Port) GetRegInfo() (*RegInfo, error) {
+	var info C.struct_rte_dev_reg_info
+
+	// retrieve length and width of registers
+	if err := errget(C.rte_eth_dev_get_reg_info(C.ushort(pid), &info)); err != nil {
+		return nil, err
+	}
+
+	size := C.size_t(info.length) * C.size_t(info.width)
+	if size == 0 {
+		return &RegInfo{Width: uint32(info.width), Version: uint32(info.version)}, nil
+	}
+
+	info.data = C.malloc(size)
+	defer C.free(info.data)
+	C.memset(info.data, 0, size)
+
+	if err := errget(C.rte_eth_dev_get_reg_info(C.ushort(pid), &info)); err != nil {
+		return nil, err
+	}
+
+	return &RegInfo{
+		Data:    C.GoBytes(info.data, C.int(size)),
+		Offset:  uint32(info.offset),
+		Width:   uint32(info.width),
+		Version: uint32(info.version),
+	}, nil
+}
+
+// DCBInfo is the DCB information of the device.
+type DCBInfo C.struct_rte_eth_dcb_info
+
+// Maximum number of DCB traffic classes, user priorities and VMDq
+// pools.
+const (
+	DCBNumTCs            = C.RTE_ETH_DCB_NUM_TCS
+	DCBNumUserPriorities = C.RTE_ETH_DCB_NUM_USER_PRIORITIES
+	DCBMaxPools          = C.RTE_ETH_MAX_VMDQ_POOL
+)
+
+func dcbIndexValid(pool, tc int) bool {
+	return pool >= 0 && pool < DCBMaxPools && tc >= 0 && tc < DCBNumTCs
+}
+
+// NbTCs returns number of TCs.
+func (info *DCBInfo) NbTCs() uint8 {
+	return uint8(info.nb_tcs)
+}
+
+// PrioTC returns TC the user priority is mapped to.
+func (info *DCBInfo) PrioTC() [DCBNumUserPriorities]uint8 {
+	return *(*[DCBNumUserPriorities]uint8)(unsafe.Pointer(&info.prio_tc))
+}
+
+// TCBandwidth returns TX bandwidth percentage of each TC.
+func (info *DCBInfo) TCBandwidth() [DCBNumTCs]uint8 {
+	return *(*[DCBNumTCs]uint8)(unsafe.Pointer(&info.tc_bws))
+}
+
+// RxQueues returns the base index and the number of Rx queues
+// assigned to TC in VMDq pool. Zeros are returned if pool or tc is
+// out of range.
+func (info *DCBInfo) RxQueues(pool, tc int) (base, n uint16) {
+	if !dcbIndexValid(pool, tc) {
+		return 0, 0
+	}
+	p := (*C.struct_rte_eth_dcb_info)(info)
+	return uint16(C.dcb_tc_rxq_base(p, C.int(pool), C.int(tc))),
+		uint16(C.dcb_tc_rxq_nb(p, C.int(pool), C.int(tc)))
+}
+
+// TxQueues returns the base index and the number of Tx queues
+// assigned to TC in VMDq pool. Zeros are returned if pool or tc is
+// out of range.
+func (info *DCBInfo) TxQueues(pool, tc int) (base, n uint16) {
+	if !dcbIndexValid(pool, tc) {
+		return 0, 0
+	}
+	p := (*C.struct_rte_eth_dcb_info)(info)
+	return uint16(C.dcb_tc_txq_base(p, C.int(pool), C.int(tc))),
+		uint16(C.dcb_tc_txq_nb(p, C.int(pool), C.int(tc)))
+}
+
+// GetDCBInfo retrieves the DCB information of the device.
+//
+// Returns:
+//
+//	(-ENODEV) if port identifier is invalid.
+//	(-EIO) if device is removed.
+//	(-ENOTSUP) if hardware doesn't support.
+func (pid Port) GetDCBInfo(info *DCBInfo) error {
+	return errget(C.rte_eth_dev_get_dcb_info(C.ushort(pid), (*C.struct_rte_eth_dcb_info)(info)))
+}
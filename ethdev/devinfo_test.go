@@ -0,0 +1,55 @@
+package ethdev
+
+import (
+	"errors"
+	"math"
+	"syscall"
+	"testing"
+
+	"github.com/yerden/go-dpdk/eal"
+)
+
+func TestDevInfoExt(t *testing.T) {
+	eal.InitOnceSafe("test", 4)
+
+	pid := Port(0)
+
+	var info DevInfo
+	err := pid.InfoGet(&info)
+	assert(t, err == nil, err)
+
+	lim := info.RxDescLim()
+	assert(t, lim.NbMax == math.MaxUint16, lim)
+	assert(t, lim.NbAlign == 1, lim)
+	assert(t, info.HashKeySize() == 40, info.HashKeySize())
+	assert(t, info.FlowTypeRssOffloads() != 0)
+	assert(t, info.SwitchInfo().Name == "", info.SwitchInfo())
+
+	// net_null0 doesn't support any of these
+	_, err = pid.FwVersionGet()
+	assert(t, errors.Is(err, syscall.ENOTSUP), err)
+
+	_, err = pid.GetModuleInfo()
+	assert(t, errors.Is(err, syscall.ENOTSUP), err)
+
+	_, err = pid.Transceiver()
+	assert(t, errors.Is(err, syscall.ENOTSUP), err)
+
+	_, err = pid.GetEEPROMLength()
+	assert(t, errors.Is(err, syscall.ENOTSUP), err)
+
+	_, err = pid.GetRegInfo()
+	assert(t, errors.Is(err, syscall.ENOTSUP), err)
+
+	var dcb DCBInfo
+	err = pid.GetDCBInfo(&dcb)
+	assert(t, errors.Is(err, syscall.ENOTSUP), err)
+
+	// out of range indices
+	for _, idx := range [][2]int{{-1, 0}, {0, -1}, {DCBMaxPools, 0}, {0, DCBNumTCs}} {
+		base, n := dcb.RxQueues(idx[0], idx[1])
+		assert(t, base == 0 && n == 0, idx)
+		base, n = dcb.TxQueues(idx[0], idx[1])
+		assert(t, base == 0 && n == 0, idx)
+	}
+}
@@ -7,6 +7,7 @@ package ethdev
 
 /*
 #include <stdlib.h>
+#include <string.h>
 #include <net/if.h>
 
 #include <rte_config.h>
@@ -107,6 +108,17 @@ static int go_rte_eth_link_get_nowait(uint16_t port_id, struct go_rte_eth_link *
 	return rc;
 }
 
+static int go_rte_eth_link_to_str(char *str, size_t len, const struct go_rte_eth_link *link)
+{
+	struct rte_eth_link data;
+	memset(&data, 0, sizeof(data));
+	data.link_speed = link->link_speed;
+	data.link_duplex = link->link_duplex;
+	data.link_autoneg = link->link_autoneg;
+	data.link_status = link->link_status;
+	return rte_eth_link_to_str(str, len, &data);
+}
+
 */
 import "C"
 
@@ -829,6 +841,12 @@ func (info *DevInfo) InterfaceName() string {
 	return C.GoString(C.if_indextoname(info.if_index, &buf[0]))
 }
 
+// Device returns the pointer to generic device information (struct
+// rte_device), e.g. for memzone.DMAMap.
+func (info *DevInfo) Device() unsafe.Pointer {
+	return unsafe.Pointer((*C.struct_rte_eth_dev_info)(info).device)
+}
+
 // RetaSize returns Device redirection table size, the total number of
 // entries.
 func (info *DevInfo) RetaSize() uint16 {
@@ -1024,6 +1042,15 @@ func (link *EthLink) Status() bool {
 	return link.link_status > 0
 }
 
+// String implements fmt.Stringer interface. It formats the link
+// status using rte_eth_link_to_str, e.g. "Link up at 10 Gbps FDX
+// Autoneg".
+func (link *EthLink) String() string {
+	var buf [C.RTE_ETH_LINK_MAX_STR_LEN]C.char
+	C.go_rte_eth_link_to_str(&buf[0], C.size_t(len(buf)), (*C.struct_go_rte_eth_link)(link))
+	return C.GoString(&buf[0])
+}
+
 // EthLinkGet retrieves the link status (up/down), the duplex mode
 // (half/full), the negotiation (auto/fixed), and if available, the
 // speed (Mbps).
@@ -0,0 +1,110 @@
+package flow
+
+/*
+#include <stdint.h>
+#include <rte_config.h>
+#include <rte_flow.h>
+
+static void set_query_count_reset(struct rte_flow_query_count *q, uint32_t reset) {
+	q->reset = reset;
+}
+
+static int get_query_count_hits_set(const struct rte_flow_query_count *q) {
+	return q->hits_set;
+}
+
+static int get_query_count_bytes_set(const struct rte_flow_query_count *q) {
+	return q->bytes_set;
+}
+*/
+import "C"
+import (
+	"runtime"
+	"unsafe"
+
+	"github.com/yerden/go-dpdk/common"
+	"github.com/yerden/go-dpdk/ethdev"
+)
+
+var _ Action = (*ActionCount)(nil)
+
+// ActionCount implements Action which enables counters for the flow
+// rule.
+//
+// These counters can be retrieved and reset through Query or, if the
+// counter is shared by means of indirect action, through
+// ActionHandleQueryCount.
+type ActionCount struct {
+	cPointer
+
+	// Counter ID.
+	ID uint32
+}
+
+// Reload implements Action interface.
+func (action *ActionCount) Reload() {
+	cptr := (*C.struct_rte_flow_action_count)(action.createOrRet(C.sizeof_struct_rte_flow_action_count))
+
+	cptr.id = C.uint32_t(action.ID)
+	runtime.SetFinalizer(action, (*ActionCount).free)
+}
+
+// Type implements Action interface.
+func (action *ActionCount) Type() ActionType {
+	return ActionTypeCount
+}
+
+// QueryCount is the query structure for COUNT action.
+type QueryCount struct {
+	// Reset counters after query (input).
+	Reset bool
+
+	// HitsSet is true if Hits field is set (output).
+	HitsSet bool
+
+	// BytesSet is true if Bytes field is set (output).
+	BytesSet bool
+
+	// Number of hits for this rule (output).
+	Hits uint64
+
+	// Number of bytes through this rule (output).
+	Bytes uint64
+}
+
+func (q *QueryCount) cQuery() (out C.struct_rte_flow_query_count) {
+	if q.Reset {
+		C.set_query_count_reset(&out, 1)
+	}
+	return
+}
+
+func (q *QueryCount) fromC(c *C.struct_rte_flow_query_count) {
+	q.HitsSet = C.get_query_count_hits_set(c) != 0
+	q.BytesSet = C.get_query_count_bytes_set(c) != 0
+	q.Hits = uint64(c.hits)
+	q.Bytes = uint64(c.bytes)
+}
+
+// Query an existing flow rule for COUNT action.
+//
+// This function allows retrieving flow-specific data such as
+// counters. Data is gathered by special actions which must be
+// present in the flow rule definition.
+//
+// action should be the ActionCount specified at flow rule creation.
+// q is filled with retrieved counters. If q.Reset is true then
+// counters are reset after query.
+func Query(port ethdev.Port, flow *Flow, action Action, q *QueryCount, flowErr *Error) error {
+	act := cActions([]Action{action})
+	data := q.cQuery()
+	rc := C.rte_flow_query(C.ushort(port), (*C.struct_rte_flow)(flow), &act[0],
+		unsafe.Pointer(&data), (*C.struct_rte_flow_error)(flowErr))
+	runtime.KeepAlive(action)
+	if rc != 0 {
+		return common.IntToErr(rc)
+	}
+
+	q.fromC(&data)
+	return nil
+}
@@ -0,0 +1,156 @@
+package flow
+
+/*
+#include <stdint.h>
+#include <rte_config.h>
+#include <rte_flow.h>
+
+static void set_indir_action_conf(struct rte_flow_indir_action_conf *conf,
+		uint32_t ingress, uint32_t egress, uint32_t transfer) {
+	conf->ingress = ingress;
+	conf->egress = egress;
+	conf->transfer = transfer;
+}
+*/
+import "C"
+import (
+	"runtime"
+	"unsafe"
+
+	"github.com/yerden/go-dpdk/common"
+	"github.com/yerden/go-dpdk/ethdev"
+)
+
+var _ Action = (*ActionIndirect)(nil)
+
+// ActionHandle is the opaque handle of an indirect action.
+//
+// Indirect action is the action which may be shared by multiple flow
+// rules. For example, a single RSS configuration or counter may be
+// referenced by many flows via ActionIndirect and its configuration
+// may be changed atomically for all of them with ActionHandleUpdate.
+type ActionHandle C.struct_rte_flow_action_handle
+
+// IndirActionConf is the indirect action configuration.
+//
+// Action configuration specifies the direction of traffic the
+// indirect action will be applied to. At least one direction must be
+// specified.
+type IndirActionConf struct {
+	// Action valid for rules applied to ingress traffic.
+	Ingress bool
+
+	// Action valid for rules applied to egress traffic.
+	Egress bool
+
+	// Action is valid for transfer traffic, see Attr.Transfer.
+	Transfer bool
+}
+
+func (c *IndirActionConf) cvtConf() (out C.struct_rte_flow_indir_action_conf) {
+	C.set_indir_action_conf(&out, C.uint32_t(boolToU32(c.Ingress)),
+		C.uint32_t(boolToU32(c.Egress)), C.uint32_t(boolToU32(c.Transfer)))
+	return
+}
+
+func boolToU32(b bool) uint32 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// ActionHandleCreate creates an indirect action object that can be
+// used in flow rules via its handle.
+//
+// The created object handle has single state and configuration
+// across all the flow rules using it.
+//
+// port is port identifier of Ethernet device. conf is the action
+// configuration for the indirect action object creation. action is
+// the specific configuration of the indirect action object, e.g.
+// ActionRSS or ActionCount. flowErr performs verbose error reporting
+// if not nil.
+//
+// Returns a valid handle in case of success, error otherwise. ENODEV
+// is returned if port is invalid, ENOSYS if underlying device does
+// not support this functionality, EIO if underlying device is removed
+// and ENOTSUP if action is valid but unsupported.
+func ActionHandleCreate(port ethdev.Port, conf *IndirActionConf, action Action, flowErr *Error) (*ActionHandle, error) {
+	act := cActions([]Action{action})
+	cConf := conf.cvtConf()
+	h := C.rte_flow_action_handle_create(C.ushort(port), &cConf, &act[0],
+		(*C.struct_rte_flow_error)(flowErr))
+	runtime.KeepAlive(action)
+	if h == nil {
+		return nil, common.RteErrno()
+	}
+
+	return (*ActionHandle)(h), nil
+}
+
+// ActionHandleDestroy destroys indirect action by handle.
+//
+// EBUSY is returned if action handle is still referenced by some
+// flow rules.
+func ActionHandleDestroy(port ethdev.Port, handle *ActionHandle, flowErr *Error) error {
+	return common.IntToErr(C.rte_flow_action_handle_destroy(C.ushort(port),
+		(*C.struct_rte_flow_action_handle)(handle),
+		(*C.struct_rte_flow_error)(flowErr)))
+}
+
+// ActionHandleUpdate updates in-place the action configuration and /
+// or state pointed by action handle with the configuration provided
+// as update argument. The update of the action configuration
+// effects all flow rules reusing the action via handle.
+//
+// update should be of the same type as the action specified in
+// ActionHandleCreate, e.g. ActionRSS with updated list of queues.
+//
+// EBUSY may be returned if the action can not be updated at the
+// moment, ENOTSUP if the update is valid but unsupported.
+func ActionHandleUpdate(port ethdev.Port, handle *ActionHandle, update Action, flowErr *Error) error {
+	act := cActions([]Action{update})
+	rc := C.rte_flow_action_handle_update(C.ushort(port),
+		(*C.struct_rte_flow_action_handle)(handle), unsafe.Pointer(&act[0]),
+		(*C.struct_rte_flow_error)(flowErr))
+	runtime.KeepAlive(update)
+	return common.IntToErr(rc)
+}
+
+// ActionHandleQueryCount queries the shared counter created as an
+// indirect action of COUNT type. q is filled with retrieved counters.
+// If q.Reset is true then counters are reset after query.
+func ActionHandleQueryCount(port ethdev.Port, handle *ActionHandle, q *QueryCount, flowErr *Error) error {
+	data := q.cQuery()
+	rc := C.rte_flow_action_handle_query(C.ushort(port),
+		(*C.struct_rte_flow_action_handle)(handle), unsafe.Pointer(&data),
+		(*C.struct_rte_flow_error)(flowErr))
+	if rc != 0 {
+		return common.IntToErr(rc)
+	}
+
+	q.fromC(&data)
+	return nil
+}
+
+// ActionIndirect implements Action which references indirect action
+// by its handle. Use it in flow rules instead of specifying the
+// action itself.
+type ActionIndirect struct {
+	Handle *ActionHandle
+}
+
+// Reload implements Action interface.
+func (action *ActionIndirect) Reload() {}
+
+// Pointer implements Action interface. The configuration of indirect
+// action is the handle itself.
+func (action *ActionIndirect) Pointer() unsafe.Pointer {
+	return unsafe.Pointer(action.Handle)
+}
+
+// Type implements Action interface.
+func (action *ActionIndirect) Type() ActionType {
+	return ActionTypeIndirect
+}
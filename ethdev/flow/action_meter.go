@@ -0,0 +1,36 @@
+package flow
+
+/*
+#include <stdint.h>
+#include <rte_config.h>
+#include <rte_flow.h>
+*/
+import "C"
+import (
+	"runtime"
+)
+
+var _ Action = (*ActionMeter)(nil)
+
+// ActionMeter implements Action which applies traffic metering and
+// policing (MTR) object to packets. See mtr package for MTR object
+// configuration.
+type ActionMeter struct {
+	cPointer
+
+	// MTR object ID created with mtr.Create.
+	MtrID uint32
+}
+
+// Reload implements Action interface.
+func (action *ActionMeter) Reload() {
+	cptr := (*C.struct_rte_flow_action_meter)(action.createOrRet(C.sizeof_struct_rte_flow_action_meter))
+
+	cptr.mtr_id = C.uint32_t(action.MtrID)
+	runtime.SetFinalizer(action, (*ActionMeter).free)
+}
+
+// Type implements Action interface.
+func (action *ActionMeter) Type() ActionType {
+	return ActionTypeMeter
+}
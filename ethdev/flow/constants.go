@@ -629,6 +629,14 @@ const (
 	 * See struct rte_flow_action_set_mac.
 	 */
 	ActionTypeSetMacDst ActionType = C.RTE_FLOW_ACTION_TYPE_SET_MAC_DST
+
+	/**
+	 * Describe action shared across multiple flow rules.
+	 *
+	 * Allow multiple rules reference the same action by handle (see
+	 * struct rte_flow_action_handle).
+	 */
+	ActionTypeIndirect ActionType = C.RTE_FLOW_ACTION_TYPE_INDIRECT
 )
 
 // HashFunction represents hash functions for RSS.
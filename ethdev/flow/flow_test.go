@@ -18,3 +18,29 @@ func TestCPattern(t *testing.T) {
 	assert(t, pat != nil)
 
 }
+
+func TestCActionsIndirect(t *testing.T) {
+	actions := []Action{
+		&ActionIndirect{},
+		&ActionCount{ID: 1},
+		ActionTypeDrop,
+	}
+
+	act := cActions(actions)
+	assert(t, len(act) == len(actions)+1)
+	assert(t, ActionType(act[0]._type) == ActionTypeIndirect)
+	assert(t, act[0].conf == nil)
+	assert(t, ActionType(act[1]._type) == ActionTypeCount)
+	assert(t, act[1].conf != nil)
+	assert(t, ActionType(act[3]._type) == ActionTypeEnd)
+}
+
+func TestQueryCount(t *testing.T) {
+	q := &QueryCount{Reset: true, HitsSet: true, Hits: 10}
+	data := q.cQuery()
+	assert(t, data.hits == 0)
+
+	q.fromC(&data)
+	assert(t, !q.HitsSet && !q.BytesSet)
+	assert(t, q.Hits == 0 && q.Bytes == 0)
+}
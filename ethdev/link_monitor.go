@@ -0,0 +1,231 @@
+package ethdev
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// LinkEvent is the link state change of a port delivered by
+// LinkMonitor.
+type LinkEvent struct {
+	// Port which link has changed.
+	Port Port
+
+	// Previously reported link state.
+	Old EthLink
+
+	// Current link state.
+	New EthLink
+}
+
+// LinkMonitorOption is the option for NewLinkMonitor.
+type LinkMonitorOption struct {
+	f func(*LinkMonitor)
+}
+
+// OptLinkPollInterval specifies the interval of link status polling.
+// Default is 100 milliseconds.
+func OptLinkPollInterval(d time.Duration) LinkMonitorOption {
+	return LinkMonitorOption{func(m *LinkMonitor) {
+		m.interval = d
+	}}
+}
+
+// OptLinkDebounce specifies the period of time the link state should
+// stay unchanged before the event is delivered to subscribers. Link
+// flaps shorter than this period are suppressed. Default is 0, i.e.
+// every observed change is delivered.
+func OptLinkDebounce(d time.Duration) LinkMonitorOption {
+	return LinkMonitorOption{func(m *LinkMonitor) {
+		m.debounce = d
+	}}
+}
+
+type linkState struct {
+	// last reported link state
+	link EthLink
+
+	// changed link state waiting for debounce period to expire
+	pending      EthLink
+	pendingSince time.Time
+	hasPending   bool
+}
+
+// LinkMonitor tracks link status of all valid ports and delivers link
+// state changes to subscribers. The ports are polled with
+// EthLinkGetNowait so that slow PMDs don't block the monitor. The
+// list of valid ports is refreshed on every poll so hot-plugged ports
+// are picked up automatically. The initial link state of a port is
+// recorded without delivering an event.
+type LinkMonitor struct {
+	interval time.Duration
+	debounce time.Duration
+
+	// for testing
+	ports   func() []Port
+	linkGet func(Port) (EthLink, error)
+
+	mu    sync.Mutex
+	state map[Port]*linkState
+	subs  map[<-chan LinkEvent]*linkSub
+}
+
+type linkSub struct {
+	ch   chan LinkEvent
+	done chan struct{}
+}
+
+// NewLinkMonitor creates new LinkMonitor. Call Run to start
+// monitoring.
+func NewLinkMonitor(opts ...LinkMonitorOption) *LinkMonitor {
+	m := &LinkMonitor{
+		interval: 100 * time.Millisecond,
+		ports:    ValidPorts,
+		linkGet:  Port.EthLinkGetNowait,
+		state:    make(map[Port]*linkState),
+		subs:     make(map[<-chan LinkEvent]*linkSub),
+	}
+
+	for i := range opts {
+		opts[i].f(m)
+	}
+
+	return m
+}
+
+// Subscribe returns the channel with buffer size n on which link
+// events are delivered. The subscriber should drain the channel
+// since the monitor blocks until the event is delivered.
+func (m *LinkMonitor) Subscribe(n int) <-chan LinkEvent {
+	sub := &linkSub{make(chan LinkEvent, n), make(chan struct{})}
+	m.mu.Lock()
+	m.subs[sub.ch] = sub
+	m.mu.Unlock()
+	return sub.ch
+}
+
+// Unsubscribe stops delivery of events to ch. The channel is not
+// closed.
+func (m *LinkMonitor) Unsubscribe(ch <-chan LinkEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if sub, ok := m.subs[ch]; ok {
+		delete(m.subs, ch)
+		close(sub.done)
+	}
+}
+
+// Link returns the last reported link state of the port. Returns
+// false if the port is not tracked.
+func (m *LinkMonitor) Link(pid Port) (EthLink, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if s, ok := m.state[pid]; ok {
+		return s.link, true
+	}
+	return EthLink{}, false
+}
+
+// Run polls the ports until ctx is cancelled. Channels of all
+// remaining subscribers are closed upon return.
+func (m *LinkMonitor) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	defer m.closeAll()
+
+	for {
+		if !m.deliver(ctx, m.poll(time.Now())) {
+			return ctx.Err()
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *LinkMonitor) closeAll() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for c, sub := range m.subs {
+		delete(m.subs, c)
+		close(sub.ch)
+	}
+}
+
+// poll queries the links and returns the events to deliver.
+func (m *LinkMonitor) poll(now time.Time) []LinkEvent {
+	var events []LinkEvent
+	ports := m.ports()
+	valid := make(map[Port]bool, len(ports))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, pid := range ports {
+		valid[pid] = true
+		link, err := m.linkGet(pid)
+		if err != nil {
+			continue
+		}
+
+		s, ok := m.state[pid]
+		if !ok {
+			m.state[pid] = &linkState{link: link}
+			continue
+		}
+
+		if link == s.link {
+			s.hasPending = false
+			continue
+		}
+
+		if !s.hasPending || link != s.pending {
+			s.pending, s.pendingSince, s.hasPending = link, now, true
+		}
+
+		if now.Sub(s.pendingSince) >= m.debounce {
+			events = append(events, LinkEvent{pid, s.link, link})
+			s.link, s.hasPending = link, false
+		}
+	}
+
+	for pid := range m.state {
+		if !valid[pid] {
+			delete(m.state, pid)
+		}
+	}
+
+	return events
+}
+
+// deliver sends events to all subscribers. Returns false if ctx is
+// cancelled.
+func (m *LinkMonitor) deliver(ctx context.Context, events []LinkEvent) bool {
+	if len(events) == 0 {
+		return true
+	}
+
+	m.mu.Lock()
+	subs := make([]*linkSub, 0, len(m.subs))
+	for _, sub := range m.subs {
+		subs = append(subs, sub)
+	}
+	m.mu.Unlock()
+
+	for _, e := range events {
+		for _, sub := range subs {
+			select {
+			case sub.ch <- e:
+			case <-sub.done:
+			case <-ctx.Done():
+				return false
+			}
+		}
+	}
+
+	return true
+}
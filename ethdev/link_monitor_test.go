@@ -0,0 +1,102 @@
+package ethdev
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yerden/go-dpdk/eal"
+)
+
+func linkUp(up bool) EthLink {
+	var link EthLink
+	if up {
+		link.link_status = 1
+		link.link_speed = 10000
+		link.link_duplex = 1
+	}
+	return link
+}
+
+func TestLinkMonitorDebounce(t *testing.T) {
+	links := map[Port]EthLink{0: linkUp(true), 1: linkUp(true)}
+	m := NewLinkMonitor(OptLinkDebounce(time.Second))
+	m.ports = func() []Port { return []Port{0, 1} }
+	m.linkGet = func(pid Port) (EthLink, error) { return links[pid], nil }
+
+	now := time.Now()
+
+	// initial state, no events
+	assert(t, len(m.poll(now)) == 0)
+	link, ok := m.Link(0)
+	assert(t, ok && link.Status())
+
+	// link flap shorter than debounce period
+	links[0] = linkUp(false)
+	assert(t, len(m.poll(now.Add(100*time.Millisecond))) == 0)
+	links[0] = linkUp(true)
+	assert(t, len(m.poll(now.Add(200*time.Millisecond))) == 0)
+	assert(t, len(m.poll(now.Add(2*time.Second))) == 0)
+
+	// link goes down
+	links[1] = linkUp(false)
+	assert(t, len(m.poll(now.Add(3*time.Second))) == 0)
+	events := m.poll(now.Add(4 * time.Second))
+	assert(t, len(events) == 1, events)
+	assert(t, events[0].Port == 1)
+	assert(t, events[0].Old.Status() && !events[0].New.Status())
+	link, ok = m.Link(1)
+	assert(t, ok && !link.Status())
+
+	// port removed
+	m.ports = func() []Port { return []Port{0} }
+	assert(t, len(m.poll(now.Add(5*time.Second))) == 0)
+	_, ok = m.Link(1)
+	assert(t, !ok)
+}
+
+func TestLinkMonitorRun(t *testing.T) {
+	var link EthLink
+	m := NewLinkMonitor(OptLinkPollInterval(time.Millisecond))
+	m.ports = func() []Port { return []Port{0} }
+	m.linkGet = func(pid Port) (EthLink, error) { return link, nil }
+
+	ch := m.Subscribe(1)
+	unsub := m.Subscribe(0)
+	m.Unsubscribe(unsub)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- m.Run(ctx) }()
+
+	// make sure initial state is recorded
+	for {
+		if _, ok := m.Link(0); ok {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	m.mu.Lock()
+	link = linkUp(true)
+	m.mu.Unlock()
+
+	e := <-ch
+	assert(t, e.Port == 0 && e.New.Status() && !e.Old.Status())
+
+	cancel()
+	assert(t, <-done == context.Canceled)
+	_, ok := <-ch
+	assert(t, !ok)
+}
+
+func TestEthLinkString(t *testing.T) {
+	eal.InitOnceSafe("test", 4)
+
+	link := linkUp(false)
+	assert(t, link.String() == "Link down", link.String())
+
+	link, err := Port(0).EthLinkGetNowait()
+	assert(t, err == nil, err)
+	assert(t, link.String() != "")
+}
@@ -0,0 +1,6 @@
+package mtr
+
+/*
+#cgo pkg-config: libdpdk
+*/
+import "C"
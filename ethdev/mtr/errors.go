@@ -0,0 +1,72 @@
+package mtr
+
+/*
+#include <rte_config.h>
+#include <rte_mtr.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// Error is a verbose error structure definition.
+//
+// This object is normally allocated by applications and set by PMDs,
+// the message points to a constant string which does not need to be
+// freed by the application, however its pointer can be considered
+// valid only as long as its associated DPDK port remains configured.
+// Closing the underlying device or unloading the PMD invalidates it.
+//
+// Both cause and message may be NULL regardless of the error type.
+type Error C.struct_rte_mtr_error
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%v: %s", e.Unwrap(), C.GoString(e.message))
+}
+
+func (e *Error) Unwrap() error {
+	return ErrorType(e._type)
+}
+
+// Cause returns object responsible for error.
+func (e *Error) Cause() unsafe.Pointer {
+	return e.cause
+}
+
+// ErrorType is a type of an error.
+type ErrorType uint
+
+func (e ErrorType) Error() string {
+	if s, ok := errStr[e]; ok {
+		return s
+	}
+	return ""
+}
+
+var (
+	errStr = make(map[ErrorType]string)
+)
+
+func registerErr(c uint, str string) ErrorType {
+	et := ErrorType(c)
+	errStr[et] = str
+	return et
+}
+
+// Error types.
+var (
+	ErrTypeNone                   = registerErr(C.RTE_MTR_ERROR_TYPE_NONE, "No error")
+	ErrTypeUnspecified            = registerErr(C.RTE_MTR_ERROR_TYPE_UNSPECIFIED, "Cause unspecified")
+	ErrTypeMeterProfileID         = registerErr(C.RTE_MTR_ERROR_TYPE_METER_PROFILE_ID, "Meter profile ID")
+	ErrTypeMeterProfile           = registerErr(C.RTE_MTR_ERROR_TYPE_METER_PROFILE, "Meter profile")
+	ErrTypeMeterProfilePacketMode = registerErr(C.RTE_MTR_ERROR_TYPE_METER_PROFILE_PACKET_MODE, "Meter profile packet mode")
+	ErrTypeMeterPolicyID          = registerErr(C.RTE_MTR_ERROR_TYPE_METER_POLICY_ID, "Meter policy ID")
+	ErrTypeMeterPolicy            = registerErr(C.RTE_MTR_ERROR_TYPE_METER_POLICY, "Meter policy")
+	ErrTypeMtrID                  = registerErr(C.RTE_MTR_ERROR_TYPE_MTR_ID, "Meter ID")
+	ErrTypeMtrParams              = registerErr(C.RTE_MTR_ERROR_TYPE_MTR_PARAMS, "Meter parameters")
+	ErrTypeStatsMask              = registerErr(C.RTE_MTR_ERROR_TYPE_STATS_MASK, "Statistics mask")
+	ErrTypeStats                  = registerErr(C.RTE_MTR_ERROR_TYPE_STATS, "Statistics")
+	ErrTypeShared                 = registerErr(C.RTE_MTR_ERROR_TYPE_SHARED, "Shared meter")
+)
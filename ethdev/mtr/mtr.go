@@ -0,0 +1,375 @@
+/*
+Package mtr wraps RTE Traffic Metering and Policing API.
+
+This interface provides the ability to configure the traffic metering
+and policing (MTR) objects of an Ethernet device: meter profiles,
+meter policies and meter objects themselves. Meter objects are
+attached to flows with flow.ActionMeter.
+*/
+package mtr
+
+/*
+#include <stdlib.h>
+#include <string.h>
+#include <stdint.h>
+#include <rte_config.h>
+#include <rte_flow.h>
+#include <rte_mtr.h>
+
+static void set_dscp_table(struct rte_mtr_params *p, void *table) {
+	p->dscp_table = table;
+}
+*/
+import "C"
+
+import (
+	"runtime"
+	"syscall"
+	"unsafe"
+
+	"github.com/yerden/go-dpdk/common"
+	"github.com/yerden/go-dpdk/ethdev"
+	"github.com/yerden/go-dpdk/ethdev/flow"
+)
+
+// Color is the packet color as per RFC 2697, 2698 and 4115.
+type Color uint32
+
+// Packet colors.
+const (
+	ColorGreen  Color = C.RTE_COLOR_GREEN
+	ColorYellow Color = C.RTE_COLOR_YELLOW
+	ColorRed    Color = C.RTE_COLOR_RED
+	// Number of colors.
+	Colors = C.RTE_COLORS
+)
+
+// StatsType is the bit mask of meter statistics counters.
+type StatsType uint64
+
+// Meter statistics counters.
+const (
+	StatsNPktsGreen    StatsType = C.RTE_MTR_STATS_N_PKTS_GREEN
+	StatsNPktsYellow   StatsType = C.RTE_MTR_STATS_N_PKTS_YELLOW
+	StatsNPktsRed      StatsType = C.RTE_MTR_STATS_N_PKTS_RED
+	StatsNPktsDropped  StatsType = C.RTE_MTR_STATS_N_PKTS_DROPPED
+	StatsNBytesGreen   StatsType = C.RTE_MTR_STATS_N_BYTES_GREEN
+	StatsNBytesYellow  StatsType = C.RTE_MTR_STATS_N_BYTES_YELLOW
+	StatsNBytesRed     StatsType = C.RTE_MTR_STATS_N_BYTES_RED
+	StatsNBytesDropped StatsType = C.RTE_MTR_STATS_N_BYTES_DROPPED
+)
+
+// Stats is the meter statistics counters.
+type Stats struct {
+	// Number of packets passed by the policer (per color).
+	NPkts [Colors]uint64
+
+	// Number of bytes passed by the policer (per color).
+	NBytes [Colors]uint64
+
+	// Number of packets dropped by the policer.
+	NPktsDropped uint64
+
+	// Number of bytes dropped by the policer.
+	NBytesDropped uint64
+}
+
+var _ = []uintptr{
+	unsafe.Sizeof(Stats{}) - unsafe.Sizeof(C.struct_rte_mtr_stats{}),
+	unsafe.Sizeof(C.struct_rte_mtr_stats{}) - unsafe.Sizeof(Stats{}),
+}
+
+// Params is the meter object parameters.
+type Params struct {
+	// Meter profile ID, see MeterProfileAdd.
+	ProfileID uint32
+
+	// Meter policy ID, see MeterPolicyAdd.
+	PolicyID uint32
+
+	// Meter input color in case of MTR object chaining. When set to
+	// true, it indicates that the input color for the current meter
+	// object is the output color for the previous meter object in
+	// the chain, otherwise DscpTable is used.
+	UsePrevMtrColor bool
+
+	// Meter input color. When non-empty, it should contain
+	// DscpTableLen entries, one per IP DSCP value, used to translate
+	// the packet DSCP into the input color.
+	DscpTable []Color
+
+	// Enable or disable the meter at the time of creation.
+	MeterEnable bool
+
+	// Meter statistics to be enabled.
+	StatsMask StatsType
+}
+
+// Policy is the meter policy parameters. It specifies the lists of
+// flow actions applied to packets of each color. Empty list means no
+// actions are applied for the given color.
+type Policy struct {
+	Green, Yellow, Red []flow.Action
+}
+
+// Capabilities is the traffic metering and policing capabilities of
+// an Ethernet device.
+type Capabilities struct {
+	// Maximum number of MTR objects.
+	NMax uint32
+	// Maximum number of MTR objects that can be shared by multiple
+	// flows.
+	NSharedMax uint32
+	// When true, all the non-shared MTR objects have identical
+	// capabilities.
+	Identical bool
+	// When true, all the shared MTR objects have identical
+	// capabilities.
+	SharedIdentical bool
+	// Maximum number of flows that can share the same MTR object.
+	SharedNFlowsPerMtrMax uint32
+	// Maximum number of MTR objects that can be part of the same flow.
+	ChainingNMtrsPerFlowMax uint32
+	// When true, the input color for the MTR object may be the
+	// output color of the previous MTR object in the chain.
+	ChainingUsePrevMtrColorSupported bool
+	// When true, the input color for the MTR object must be the
+	// output color of the previous MTR object in the chain.
+	ChainingUsePrevMtrColorEnforced bool
+	// Maximum number of MTR objects per algorithm.
+	MeterSrTCMRFC2697NMax uint32
+	MeterTrTCMRFC2698NMax uint32
+	MeterTrTCMRFC4115NMax uint32
+	// Maximum traffic rate that can be metered by a single MTR object.
+	MeterRateMax uint64
+	// Maximum number of policy objects.
+	MeterPolicyNMax uint64
+	// Color aware mode support per algorithm.
+	ColorAwareSrTCMRFC2697Supported bool
+	ColorAwareTrTCMRFC2698Supported bool
+	ColorAwareTrTCMRFC4115Supported bool
+	// Byte and packet modes support per algorithm.
+	SrTCMRFC2697ByteModeSupported   bool
+	SrTCMRFC2697PacketModeSupported bool
+	TrTCMRFC2698ByteModeSupported   bool
+	TrTCMRFC2698PacketModeSupported bool
+	TrTCMRFC4115ByteModeSupported   bool
+	TrTCMRFC4115PacketModeSupported bool
+	// Set of supported statistics counter types.
+	StatsMask StatsType
+}
+
+func (c *Capabilities) fromC(caps *C.struct_rte_mtr_capabilities) {
+	*c = Capabilities{
+		NMax:                             uint32(caps.n_max),
+		NSharedMax:                       uint32(caps.n_shared_max),
+		Identical:                        caps.identical != 0,
+		SharedIdentical:                  caps.shared_identical != 0,
+		SharedNFlowsPerMtrMax:            uint32(caps.shared_n_flows_per_mtr_max),
+		ChainingNMtrsPerFlowMax:          uint32(caps.chaining_n_mtrs_per_flow_max),
+		ChainingUsePrevMtrColorSupported: caps.chaining_use_prev_mtr_color_supported != 0,
+		ChainingUsePrevMtrColorEnforced:  caps.chaining_use_prev_mtr_color_enforced != 0,
+		MeterSrTCMRFC2697NMax:            uint32(caps.meter_srtcm_rfc2697_n_max),
+		MeterTrTCMRFC2698NMax:            uint32(caps.meter_trtcm_rfc2698_n_max),
+		MeterTrTCMRFC4115NMax:            uint32(caps.meter_trtcm_rfc4115_n_max),
+		MeterRateMax:                     uint64(caps.meter_rate_max),
+		MeterPolicyNMax:                  uint64(caps.meter_policy_n_max),
+		ColorAwareSrTCMRFC2697Supported:  caps.color_aware_srtcm_rfc2697_supported != 0,
+		ColorAwareTrTCMRFC2698Supported:  caps.color_aware_trtcm_rfc2698_supported != 0,
+		ColorAwareTrTCMRFC4115Supported:  caps.color_aware_trtcm_rfc4115_supported != 0,
+		SrTCMRFC2697ByteModeSupported:    caps.srtcm_rfc2697_byte_mode_supported != 0,
+		SrTCMRFC2697PacketModeSupported:  caps.srtcm_rfc2697_packet_mode_supported != 0,
+		TrTCMRFC2698ByteModeSupported:    caps.trtcm_rfc2698_byte_mode_supported != 0,
+		TrTCMRFC2698PacketModeSupported:  caps.trtcm_rfc2698_packet_mode_supported != 0,
+		TrTCMRFC4115ByteModeSupported:    caps.trtcm_rfc4115_byte_mode_supported != 0,
+		TrTCMRFC4115PacketModeSupported:  caps.trtcm_rfc4115_packet_mode_supported != 0,
+		StatsMask:                        StatsType(caps.stats_mask),
+	}
+}
+
+func cerr(e *Error) *C.struct_rte_mtr_error {
+	return (*C.struct_rte_mtr_error)(e)
+}
+
+// CapabilitiesGet retrieves MTR capabilities of the port.
+func CapabilitiesGet(port ethdev.Port, caps *Capabilities, mtrErr *Error) error {
+	var c C.struct_rte_mtr_capabilities
+	if err := common.IntToErr(C.rte_mtr_capabilities_get(C.ushort(port), &c, cerr(mtrErr))); err != nil {
+		return err
+	}
+	caps.fromC(&c)
+	return nil
+}
+
+// MeterProfileAdd adds meter profile with ID profileID. The meter
+// profile is used to create one or several MTR objects.
+func MeterProfileAdd(port ethdev.Port, profileID uint32, profile Profile, mtrErr *Error) error {
+	var p C.struct_rte_mtr_meter_profile
+	profile.cvtProfile(&p)
+	return common.IntToErr(C.rte_mtr_meter_profile_add(C.ushort(port),
+		C.uint32_t(profileID), &p, cerr(mtrErr)))
+}
+
+// MeterProfileDelete deletes meter profile with ID profileID. Meter
+// profile must not be used by any existing MTR object.
+func MeterProfileDelete(port ethdev.Port, profileID uint32, mtrErr *Error) error {
+	return common.IntToErr(C.rte_mtr_meter_profile_delete(C.ushort(port),
+		C.uint32_t(profileID), cerr(mtrErr)))
+}
+
+// allocate c-style list of rte_flow_action's in C memory.
+func cActions(actions []flow.Action) *C.struct_rte_flow_action {
+	if len(actions) == 0 {
+		return nil
+	}
+
+	n := C.size_t(len(actions)+1) * C.sizeof_struct_rte_flow_action
+	p := C.malloc(n)
+	C.memset(p, 0, n)
+
+	act := unsafe.Slice((*C.struct_rte_flow_action)(p), len(actions)+1)
+	for i := range actions {
+		actions[i].Reload()
+		act[i]._type = uint32(actions[i].Type())
+		act[i].conf = actions[i].Pointer()
+	}
+
+	return &act[0]
+}
+
+func (p *Policy) cvtPolicy() (out C.struct_rte_mtr_meter_policy_params) {
+	out.actions[ColorGreen] = cActions(p.Green)
+	out.actions[ColorYellow] = cActions(p.Yellow)
+	out.actions[ColorRed] = cActions(p.Red)
+	return
+}
+
+func freePolicy(p *C.struct_rte_mtr_meter_policy_params) {
+	for i := range p.actions {
+		C.free(unsafe.Pointer(p.actions[i]))
+	}
+}
+
+// MeterPolicyValidate checks whether the meter policy is valid for
+// the given port.
+func MeterPolicyValidate(port ethdev.Port, policy *Policy, mtrErr *Error) error {
+	p := policy.cvtPolicy()
+	defer freePolicy(&p)
+	rc := C.rte_mtr_meter_policy_validate(C.ushort(port), &p, cerr(mtrErr))
+	runtime.KeepAlive(policy)
+	return common.IntToErr(rc)
+}
+
+// MeterPolicyAdd adds meter policy with ID policyID. The meter policy
+// is used to create one or several MTR objects.
+func MeterPolicyAdd(port ethdev.Port, policyID uint32, policy *Policy, mtrErr *Error) error {
+	p := policy.cvtPolicy()
+	defer freePolicy(&p)
+	rc := C.rte_mtr_meter_policy_add(C.ushort(port), C.uint32_t(policyID), &p, cerr(mtrErr))
+	runtime.KeepAlive(policy)
+	return common.IntToErr(rc)
+}
+
+// MeterPolicyDelete deletes meter policy with ID policyID. Meter
+// policy must not be used by any existing MTR object.
+func MeterPolicyDelete(port ethdev.Port, policyID uint32, mtrErr *Error) error {
+	return common.IntToErr(C.rte_mtr_meter_policy_delete(C.ushort(port),
+		C.uint32_t(policyID), cerr(mtrErr)))
+}
+
+// DscpTableLen is the number of entries in Params.DscpTable, one per
+// IP DSCP value.
+const DscpTableLen = 64
+
+// Create MTR object with ID mtrID. The meter object is attached to
+// flows with flow.ActionMeter specifying the same ID.
+//
+// If shared is true then the MTR object can be shared by multiple
+// flows, otherwise only a single flow is allowed.
+//
+// syscall.EINVAL is returned if params.DscpTable is neither empty nor
+// of DscpTableLen entries.
+func Create(port ethdev.Port, mtrID uint32, params *Params, shared bool, mtrErr *Error) error {
+	if n := len(params.DscpTable); n != 0 && n != DscpTableLen {
+		return syscall.EINVAL
+	}
+
+	var p C.struct_rte_mtr_params
+	p.meter_profile_id = C.uint32_t(params.ProfileID)
+	p.meter_policy_id = C.uint32_t(params.PolicyID)
+	p.use_prev_mtr_color = boolToInt(params.UsePrevMtrColor)
+	p.meter_enable = boolToInt(params.MeterEnable)
+	p.stats_mask = C.uint64_t(params.StatsMask)
+
+	if n := len(params.DscpTable); n > 0 {
+		sz := C.size_t(n) * C.size_t(unsafe.Sizeof(params.DscpTable[0]))
+		table := C.malloc(sz)
+		defer C.free(table)
+		C.memcpy(table, unsafe.Pointer(&params.DscpTable[0]), sz)
+		C.set_dscp_table(&p, table)
+	}
+
+	var cShared C.int
+	if shared {
+		cShared = 1
+	}
+
+	return common.IntToErr(C.rte_mtr_create(C.ushort(port), C.uint32_t(mtrID),
+		&p, cShared, cerr(mtrErr)))
+}
+
+// Destroy MTR object with ID mtrID. Applicable to MTR objects that
+// are not currently used by any flow.
+func Destroy(port ethdev.Port, mtrID uint32, mtrErr *Error) error {
+	return common.IntToErr(C.rte_mtr_destroy(C.ushort(port), C.uint32_t(mtrID), cerr(mtrErr)))
+}
+
+// MeterEnable enables the meter of MTR object with ID mtrID.
+func MeterEnable(port ethdev.Port, mtrID uint32, mtrErr *Error) error {
+	return common.IntToErr(C.rte_mtr_meter_enable(C.ushort(port), C.uint32_t(mtrID), cerr(mtrErr)))
+}
+
+// MeterDisable disables the meter of MTR object with ID mtrID. When
+// disabled, the meter object is bypassed: all packets retain their
+// input color and the policy is not applied.
+func MeterDisable(port ethdev.Port, mtrID uint32, mtrErr *Error) error {
+	return common.IntToErr(C.rte_mtr_meter_disable(C.ushort(port), C.uint32_t(mtrID), cerr(mtrErr)))
+}
+
+// MeterProfileUpdate sets meter profile with ID profileID for MTR
+// object with ID mtrID.
+func MeterProfileUpdate(port ethdev.Port, mtrID, profileID uint32, mtrErr *Error) error {
+	return common.IntToErr(C.rte_mtr_meter_profile_update(C.ushort(port),
+		C.uint32_t(mtrID), C.uint32_t(profileID), cerr(mtrErr)))
+}
+
+// MeterPolicyUpdate sets meter policy with ID policyID for MTR object
+// with ID mtrID.
+func MeterPolicyUpdate(port ethdev.Port, mtrID, policyID uint32, mtrErr *Error) error {
+	return common.IntToErr(C.rte_mtr_meter_policy_update(C.ushort(port),
+		C.uint32_t(mtrID), C.uint32_t(policyID), cerr(mtrErr)))
+}
+
+// StatsUpdate updates the set of enabled statistics counters for MTR
+// object with ID mtrID.
+func StatsUpdate(port ethdev.Port, mtrID uint32, mask StatsType, mtrErr *Error) error {
+	return common.IntToErr(C.rte_mtr_stats_update(C.ushort(port),
+		C.uint32_t(mtrID), C.uint64_t(mask), cerr(mtrErr)))
+}
+
+// StatsRead reads statistics counters of MTR object with ID mtrID
+// into s. If clear is true then the counters are cleared after
+// reading.
+//
+// Returns the mask of statistics counters that are valid in s.
+func StatsRead(port ethdev.Port, mtrID uint32, s *Stats, clear bool, mtrErr *Error) (StatsType, error) {
+	var mask C.uint64_t
+	var c C.int
+	if clear {
+		c = 1
+	}
+
+	rc := C.rte_mtr_stats_read(C.ushort(port), C.uint32_t(mtrID),
+		(*C.struct_rte_mtr_stats)(unsafe.Pointer(s)), &mask, c, cerr(mtrErr))
+	return StatsType(mask), common.IntToErr(rc)
+}
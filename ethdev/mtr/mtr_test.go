@@ -0,0 +1,58 @@
+package mtr
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/yerden/go-dpdk/eal"
+	"github.com/yerden/go-dpdk/ethdev"
+	"github.com/yerden/go-dpdk/ethdev/flow"
+)
+
+func assert(t testing.TB, expected bool, args ...interface{}) {
+	if !expected {
+		t.Helper()
+		t.Fatal(args...)
+	}
+}
+
+func TestProfile(t *testing.T) {
+	for _, p := range []Profile{
+		&SrTCMProfile{CIR: 1000, CBS: 100, EBS: 100},
+		&TrTCMProfile{CIR: 1000, PIR: 2000, CBS: 100, PBS: 200},
+		&TrTCM4115Profile{CIR: 1000, EIR: 2000, CBS: 100, EBS: 200, PacketMode: true},
+	} {
+		var c cProfile
+		p.cvtProfile(&c)
+		assert(t, Algorithm(c.alg) == p.Algorithm(), c.alg)
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	p := &Policy{
+		Green: []flow.Action{&flow.ActionQueue{Index: 1}},
+		Red:   []flow.Action{flow.ActionTypeDrop},
+	}
+
+	c := p.cvtPolicy()
+	defer freePolicy(&c)
+	assert(t, c.actions[ColorGreen] != nil)
+	assert(t, c.actions[ColorYellow] == nil)
+	assert(t, c.actions[ColorRed] != nil)
+}
+
+func TestCapabilities(t *testing.T) {
+	eal.InitOnceSafe("test", 4)
+
+	// net_null0, no support for MTR
+	var caps Capabilities
+	var e Error
+	err := CapabilitiesGet(ethdev.Port(0), &caps, &e)
+	assert(t, err != nil)
+}
+
+func TestCreateDscpTable(t *testing.T) {
+	params := &Params{DscpTable: make([]Color, 10)}
+	err := Create(ethdev.Port(0), 1, params, false, nil)
+	assert(t, err == syscall.EINVAL, err)
+}
@@ -0,0 +1,158 @@
+package mtr
+
+/*
+#include <stdint.h>
+#include <rte_config.h>
+#include <rte_mtr.h>
+
+static void set_srtcm_rfc2697(struct rte_mtr_meter_profile *p,
+		uint64_t cir, uint64_t cbs, uint64_t ebs) {
+	p->alg = RTE_MTR_SRTCM_RFC2697;
+	p->srtcm_rfc2697.cir = cir;
+	p->srtcm_rfc2697.cbs = cbs;
+	p->srtcm_rfc2697.ebs = ebs;
+}
+
+static void set_trtcm_rfc2698(struct rte_mtr_meter_profile *p,
+		uint64_t cir, uint64_t pir, uint64_t cbs, uint64_t pbs) {
+	p->alg = RTE_MTR_TRTCM_RFC2698;
+	p->trtcm_rfc2698.cir = cir;
+	p->trtcm_rfc2698.pir = pir;
+	p->trtcm_rfc2698.cbs = cbs;
+	p->trtcm_rfc2698.pbs = pbs;
+}
+
+static void set_trtcm_rfc4115(struct rte_mtr_meter_profile *p,
+		uint64_t cir, uint64_t eir, uint64_t cbs, uint64_t ebs) {
+	p->alg = RTE_MTR_TRTCM_RFC4115;
+	p->trtcm_rfc4115.cir = cir;
+	p->trtcm_rfc4115.eir = eir;
+	p->trtcm_rfc4115.cbs = cbs;
+	p->trtcm_rfc4115.ebs = ebs;
+}
+*/
+import "C"
+
+// Algorithm is the traffic metering algorithm.
+type Algorithm uint32
+
+// Traffic metering algorithms.
+const (
+	// No traffic metering performed, the output color is the same as
+	// the input color for every input packet.
+	AlgNone Algorithm = C.RTE_MTR_NONE
+	// Single Rate Three Color Marker (srTCM) - IETF RFC 2697.
+	AlgSrTCMRFC2697 Algorithm = C.RTE_MTR_SRTCM_RFC2697
+	// Two Rate Three Color Marker (trTCM) - IETF RFC 2698.
+	AlgTrTCMRFC2698 Algorithm = C.RTE_MTR_TRTCM_RFC2698
+	// Two Rate Three Color Marker (trTCM) - IETF RFC 4115.
+	AlgTrTCMRFC4115 Algorithm = C.RTE_MTR_TRTCM_RFC4115
+)
+
+// Profile is the meter profile parameters. Implemented by
+// SrTCMProfile, TrTCMProfile and TrTCM4115Profile.
+//
+// Rates are specified in bytes per second and bucket sizes are in
+// bytes unless the packet mode is set, in which case they are in
+// packets per second and packets respectively.
+type Profile interface {
+	// Algorithm returns the traffic metering algorithm of the
+	// profile.
+	Algorithm() Algorithm
+
+	cvtProfile(*C.struct_rte_mtr_meter_profile)
+}
+
+// SrTCMProfile is the Single Rate Three Color Marker (srTCM)
+// profile as per IETF RFC 2697.
+type SrTCMProfile struct {
+	// Committed Information Rate (CIR).
+	CIR uint64
+	// Committed Burst Size (CBS).
+	CBS uint64
+	// Excess Burst Size (EBS).
+	EBS uint64
+	// Meter rates are in packets per second and bucket sizes are in
+	// packets.
+	PacketMode bool
+}
+
+// TrTCMProfile is the Two Rate Three Color Marker (trTCM) profile as
+// per IETF RFC 2698.
+type TrTCMProfile struct {
+	// Committed Information Rate (CIR).
+	CIR uint64
+	// Peak Information Rate (PIR).
+	PIR uint64
+	// Committed Burst Size (CBS).
+	CBS uint64
+	// Peak Burst Size (PBS).
+	PBS uint64
+	// Meter rates are in packets per second and bucket sizes are in
+	// packets.
+	PacketMode bool
+}
+
+// TrTCM4115Profile is the Two Rate Three Color Marker (trTCM)
+// profile as per IETF RFC 4115.
+type TrTCM4115Profile struct {
+	// Committed Information Rate (CIR).
+	CIR uint64
+	// Excess Information Rate (EIR).
+	EIR uint64
+	// Committed Burst Size (CBS).
+	CBS uint64
+	// Excess Burst Size (EBS).
+	EBS uint64
+	// Meter rates are in packets per second and bucket sizes are in
+	// packets.
+	PacketMode bool
+}
+
+var (
+	_ Profile = (*SrTCMProfile)(nil)
+	_ Profile = (*TrTCMProfile)(nil)
+	_ Profile = (*TrTCM4115Profile)(nil)
+)
+
+func boolToInt(b bool) C.int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Algorithm implements Profile interface.
+func (p *SrTCMProfile) Algorithm() Algorithm {
+	return AlgSrTCMRFC2697
+}
+
+func (p *SrTCMProfile) cvtProfile(out *C.struct_rte_mtr_meter_profile) {
+	C.set_srtcm_rfc2697(out, C.uint64_t(p.CIR), C.uint64_t(p.CBS), C.uint64_t(p.EBS))
+	out.packet_mode = boolToInt(p.PacketMode)
+}
+
+// Algorithm implements Profile interface.
+func (p *TrTCMProfile) Algorithm() Algorithm {
+	return AlgTrTCMRFC2698
+}
+
+func (p *TrTCMProfile) cvtProfile(out *C.struct_rte_mtr_meter_profile) {
+	C.set_trtcm_rfc2698(out, C.uint64_t(p.CIR), C.uint64_t(p.PIR),
+		C.uint64_t(p.CBS), C.uint64_t(p.PBS))
+	out.packet_mode = boolToInt(p.PacketMode)
+}
+
+// Algorithm implements Profile interface.
+func (p *TrTCM4115Profile) Algorithm() Algorithm {
+	return AlgTrTCMRFC4115
+}
+
+func (p *TrTCM4115Profile) cvtProfile(out *C.struct_rte_mtr_meter_profile) {
+	C.set_trtcm_rfc4115(out, C.uint64_t(p.CIR), C.uint64_t(p.EIR),
+		C.uint64_t(p.CBS), C.uint64_t(p.EBS))
+	out.packet_mode = boolToInt(p.PacketMode)
+}
+
+// for testing
+type cProfile = C.struct_rte_mtr_meter_profile
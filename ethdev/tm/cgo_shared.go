@@ -0,0 +1,6 @@
+package tm
+
+/*
+#cgo pkg-config: libdpdk
+*/
+import "C"
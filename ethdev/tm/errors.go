@@ -0,0 +1,74 @@
+package tm
+
+/*
+#include <rte_config.h>
+#include <rte_tm.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// Error is a verbose error structure definition.
+//
+// This object is normally allocated by applications and set by PMDs,
+// the message points to a constant string which does not need to be
+// freed by the application, however its pointer can be considered
+// valid only as long as its associated DPDK port remains configured.
+// Closing the underlying device or unloading the PMD invalidates it.
+//
+// Both cause and message may be NULL regardless of the error type.
+type Error C.struct_rte_tm_error
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%v: %s", e.Unwrap(), C.GoString(e.message))
+}
+
+func (e *Error) Unwrap() error {
+	return ErrorType(e._type)
+}
+
+// Cause returns object responsible for error.
+func (e *Error) Cause() unsafe.Pointer {
+	return e.cause
+}
+
+// ErrorType is a type of an error.
+type ErrorType uint
+
+func (e ErrorType) Error() string {
+	if s, ok := errStr[e]; ok {
+		return s
+	}
+	return ""
+}
+
+var (
+	errStr = make(map[ErrorType]string)
+)
+
+func registerErr(c uint, str string) ErrorType {
+	et := ErrorType(c)
+	errStr[et] = str
+	return et
+}
+
+// Error types.
+var (
+	ErrTypeNone                      = registerErr(C.RTE_TM_ERROR_TYPE_NONE, "No error")
+	ErrTypeUnspecified               = registerErr(C.RTE_TM_ERROR_TYPE_UNSPECIFIED, "Cause unspecified")
+	ErrTypeCapabilities              = registerErr(C.RTE_TM_ERROR_TYPE_CAPABILITIES, "Capabilities")
+	ErrTypeLevelID                   = registerErr(C.RTE_TM_ERROR_TYPE_LEVEL_ID, "Level ID")
+	ErrTypeShaperProfile             = registerErr(C.RTE_TM_ERROR_TYPE_SHAPER_PROFILE, "Shaper profile")
+	ErrTypeShaperProfileID           = registerErr(C.RTE_TM_ERROR_TYPE_SHAPER_PROFILE_ID, "Shaper profile ID")
+	ErrTypeSharedShaperID            = registerErr(C.RTE_TM_ERROR_TYPE_SHARED_SHAPER_ID, "Shared shaper ID")
+	ErrTypeNodeParentNodeID          = registerErr(C.RTE_TM_ERROR_TYPE_NODE_PARENT_NODE_ID, "Parent node ID")
+	ErrTypeNodePriority              = registerErr(C.RTE_TM_ERROR_TYPE_NODE_PRIORITY, "Node priority")
+	ErrTypeNodeWeight                = registerErr(C.RTE_TM_ERROR_TYPE_NODE_WEIGHT, "Node weight")
+	ErrTypeNodeParams                = registerErr(C.RTE_TM_ERROR_TYPE_NODE_PARAMS, "Node parameters")
+	ErrTypeNodeParamsShaperProfileID = registerErr(C.RTE_TM_ERROR_TYPE_NODE_PARAMS_SHAPER_PROFILE_ID, "Node shaper profile ID")
+	ErrTypeNodeParamsStats           = registerErr(C.RTE_TM_ERROR_TYPE_NODE_PARAMS_STATS, "Node statistics")
+	ErrTypeNodeID                    = registerErr(C.RTE_TM_ERROR_TYPE_NODE_ID, "Node ID")
+)
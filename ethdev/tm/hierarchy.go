@@ -0,0 +1,209 @@
+package tm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/yerden/go-dpdk/ethdev"
+)
+
+// Hierarchy validation errors.
+var (
+	ErrNoRoot        = errors.New("no root node")
+	ErrMultipleRoots = errors.New("multiple root nodes")
+	ErrDuplicateNode = errors.New("duplicate node ID")
+	ErrNoParent      = errors.New("parent node not found")
+	ErrLevel         = errors.New("invalid node level")
+	ErrLeafID        = errors.New("invalid leaf node ID")
+	ErrNonLeafID     = errors.New("non-leaf node ID clashes with leaf node IDs")
+	ErrUnreachable   = errors.New("node is unreachable from root")
+)
+
+// NodeError is returned by Hierarchy validation and specifies the
+// node which failed the check.
+type NodeError struct {
+	// ID of the node.
+	ID uint32
+
+	// Validation error.
+	Err error
+}
+
+func (e *NodeError) Error() string {
+	return fmt.Sprintf("node %d: %v", e.ID, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *NodeError) Unwrap() error {
+	return e.Err
+}
+
+// Node is the node of Hierarchy. See NodeAdd for the description of
+// fields.
+type Node struct {
+	// Node ID.
+	ID uint32
+
+	// Parent node ID. Specify NodeIDNull for the root node.
+	ParentID uint32
+
+	// Node priority relative to its sibling nodes.
+	Priority uint32
+
+	// Node weight relative to its sibling nodes of the same priority.
+	Weight uint32
+
+	// Node level, e.g. LevelPort. Specify NodeLevelIDAny to skip
+	// level checking.
+	Level uint32
+
+	// Node parameters.
+	Opts []NodeOption
+}
+
+// Hierarchy is the description of node tree built from the root node
+// (port) to leaf nodes (TX queues), e.g. port -> subport -> pipe ->
+// traffic class -> queue.
+//
+// The tree is validated with Validate before it's applied to the
+// device so that the errors in the hierarchy are detected before any
+// changes to the device are made.
+type Hierarchy struct {
+	Nodes []Node
+}
+
+// Add appends nodes to the hierarchy.
+func (h *Hierarchy) Add(nodes ...Node) {
+	h.Nodes = append(h.Nodes, nodes...)
+}
+
+// Validate checks the hierarchy for correctness given that the
+// device has nLeaves leaf nodes (i.e. TX queues). The checks are:
+//
+// * there is exactly one root node;
+//
+// * node IDs are unique;
+//
+// * each non-root node has its parent in the hierarchy and is
+// reachable from the root;
+//
+// * node level, if specified, is one more than the parent's level,
+// the root node being at level 0;
+//
+// * leaf nodes have IDs in range [0, nLeaves) and non-leaf nodes
+// have IDs out of this range.
+//
+// Returns NodeError in case of failure.
+func (h *Hierarchy) Validate(nLeaves uint32) error {
+	nodes := make(map[uint32]*Node, len(h.Nodes))
+	children := make(map[uint32]int, len(h.Nodes))
+	var root *Node
+
+	for i := range h.Nodes {
+		n := &h.Nodes[i]
+		if _, ok := nodes[n.ID]; ok {
+			return &NodeError{n.ID, ErrDuplicateNode}
+		}
+		nodes[n.ID] = n
+
+		if n.ParentID != NodeIDNull {
+			children[n.ParentID]++
+		} else if root != nil {
+			return &NodeError{n.ID, ErrMultipleRoots}
+		} else {
+			root = n
+		}
+	}
+
+	if root == nil {
+		return ErrNoRoot
+	}
+
+	if root.Level != NodeLevelIDAny && root.Level != 0 {
+		return &NodeError{root.ID, ErrLevel}
+	}
+
+	for i := range h.Nodes {
+		n := &h.Nodes[i]
+		if n != root {
+			parent, ok := nodes[n.ParentID]
+			if !ok {
+				return &NodeError{n.ID, ErrNoParent}
+			}
+
+			if n.Level != NodeLevelIDAny && parent.Level != NodeLevelIDAny &&
+				n.Level != parent.Level+1 {
+				return &NodeError{n.ID, ErrLevel}
+			}
+
+			// walk up to the root
+			p, hops := n, 0
+			for p != root {
+				if hops++; hops > len(h.Nodes) {
+					return &NodeError{n.ID, ErrUnreachable}
+				}
+				if p = nodes[p.ParentID]; p == nil {
+					return &NodeError{n.ID, ErrUnreachable}
+				}
+			}
+		}
+
+		if _, ok := children[n.ID]; ok {
+			if n.ID < nLeaves {
+				return &NodeError{n.ID, ErrNonLeafID}
+			}
+		} else if n.ID >= nLeaves {
+			return &NodeError{n.ID, ErrLeafID}
+		}
+	}
+
+	return nil
+}
+
+// sorted returns nodes in the order of addition: every node comes
+// after its parent. Hierarchy must be valid.
+func (h *Hierarchy) sorted() []*Node {
+	out := make([]*Node, 0, len(h.Nodes))
+	added := make(map[uint32]bool, len(h.Nodes))
+
+	for len(out) < len(h.Nodes) {
+		for i := range h.Nodes {
+			n := &h.Nodes[i]
+			if added[n.ID] {
+				continue
+			}
+			if n.ParentID == NodeIDNull || added[n.ParentID] {
+				out = append(out, n)
+				added[n.ID] = true
+			}
+		}
+	}
+
+	return out
+}
+
+// Apply validates the hierarchy, adds all its nodes to the port in
+// the order from the root to the leaves and commits the hierarchy.
+// Shaper profiles and shared shapers referenced by the nodes should
+// be added beforehand.
+//
+// If clearOnFail is true then the hierarchy is cleared on failure.
+func (h *Hierarchy) Apply(port ethdev.Port, clearOnFail bool, tmErr *Error) error {
+	nLeaves, err := LeafNodesNum(port, tmErr)
+	if err != nil {
+		return err
+	}
+
+	if err := h.Validate(nLeaves); err != nil {
+		return err
+	}
+
+	for _, n := range h.sorted() {
+		if err := NodeAdd(port, n.ID, n.ParentID, n.Priority, n.Weight,
+			n.Level, tmErr, n.Opts...); err != nil {
+			return &NodeError{n.ID, err}
+		}
+	}
+
+	return HierarchyCommit(port, clearOnFail, tmErr)
+}
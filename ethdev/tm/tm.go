@@ -0,0 +1,484 @@
+/*
+Package tm wraps RTE Traffic Management API.
+
+This interface provides the ability to configure the hierarchical
+scheduler of an Ethernet device on egress: shaper profiles, node
+hierarchy and statistics. The hierarchy may be built node by node
+with NodeAdd or described with Hierarchy which validates the tree
+before it's applied to the device.
+*/
+package tm
+
+/*
+#include <stdlib.h>
+#include <string.h>
+#include <stdint.h>
+#include <rte_config.h>
+#include <rte_tm.h>
+
+static void set_node_params_nonleaf(struct rte_tm_node_params *p,
+		uint32_t n_sp_priorities) {
+	p->nonleaf.wfq_weight_mode = NULL;
+	p->nonleaf.n_sp_priorities = n_sp_priorities;
+}
+
+static void set_node_params_leaf(struct rte_tm_node_params *p,
+		uint32_t wred_profile_id) {
+	p->leaf.cman = wred_profile_id == RTE_TM_WRED_PROFILE_ID_NONE ?
+		RTE_TM_CMAN_TAIL_DROP : RTE_TM_CMAN_WRED;
+	p->leaf.wred.wred_profile_id = wred_profile_id;
+}
+*/
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/yerden/go-dpdk/common"
+	"github.com/yerden/go-dpdk/ethdev"
+)
+
+// Special identifiers.
+const (
+	// Invalid node ID. Used as parent node ID of the root node.
+	NodeIDNull uint32 = C.RTE_TM_NODE_ID_NULL
+	// Node level ID used to disable level ID checking.
+	NodeLevelIDAny uint32 = C.RTE_TM_NODE_LEVEL_ID_ANY
+	// Invalid shaper profile ID. Used to disable private shaper of a
+	// node.
+	ShaperProfileIDNone uint32 = C.RTE_TM_SHAPER_PROFILE_ID_NONE
+	// Invalid WRED profile ID. Used to disable WRED of a leaf node.
+	WredProfileIDNone uint32 = C.RTE_TM_WRED_PROFILE_ID_NONE
+)
+
+// Levels of the typical hierarchy used in DPDK QoS scheduler.
+const (
+	LevelPort uint32 = iota
+	LevelSubport
+	LevelPipe
+	LevelTC
+	LevelQueue
+)
+
+// StatsType is the bit mask of node statistics counters.
+type StatsType uint64
+
+// Node statistics counters.
+const (
+	StatsNPkts               StatsType = C.RTE_TM_STATS_N_PKTS
+	StatsNBytes              StatsType = C.RTE_TM_STATS_N_BYTES
+	StatsNPktsGreenDropped   StatsType = C.RTE_TM_STATS_N_PKTS_GREEN_DROPPED
+	StatsNPktsYellowDropped  StatsType = C.RTE_TM_STATS_N_PKTS_YELLOW_DROPPED
+	StatsNPktsRedDropped     StatsType = C.RTE_TM_STATS_N_PKTS_RED_DROPPED
+	StatsNBytesGreenDropped  StatsType = C.RTE_TM_STATS_N_BYTES_GREEN_DROPPED
+	StatsNBytesYellowDropped StatsType = C.RTE_TM_STATS_N_BYTES_YELLOW_DROPPED
+	StatsNBytesRedDropped    StatsType = C.RTE_TM_STATS_N_BYTES_RED_DROPPED
+	StatsNPktsQueued         StatsType = C.RTE_TM_STATS_N_PKTS_QUEUED
+	StatsNBytesQueued        StatsType = C.RTE_TM_STATS_N_BYTES_QUEUED
+)
+
+// NodeStats is the node statistics counters.
+type NodeStats struct {
+	// Number of packets scheduled from current node.
+	NPkts uint64
+
+	// Number of bytes scheduled from current node.
+	NBytes uint64
+
+	// Statistics counters for leaf nodes only.
+	Leaf struct {
+		// Number of packets dropped by current leaf node per each
+		// color.
+		NPktsDropped [C.RTE_COLORS]uint64
+
+		// Number of bytes dropped by current leaf node per each
+		// color.
+		NBytesDropped [C.RTE_COLORS]uint64
+
+		// Number of packets currently waiting in the packet queue of
+		// current leaf node.
+		NPktsQueued uint64
+
+		// Number of bytes currently waiting in the packet queue of
+		// current leaf node.
+		NBytesQueued uint64
+	}
+}
+
+var _ = []uintptr{
+	unsafe.Sizeof(NodeStats{}) - unsafe.Sizeof(C.struct_rte_tm_node_stats{}),
+	unsafe.Sizeof(C.struct_rte_tm_node_stats{}) - unsafe.Sizeof(NodeStats{}),
+}
+
+// TokenBucket is the token bucket parameters.
+type TokenBucket struct {
+	// Token bucket rate (bytes per second or packets per second).
+	Rate uint64
+
+	// Token bucket size (bytes or packets), a.k.a. max burst size.
+	Size uint64
+}
+
+// ShaperParams is the shaper (rate limiter) profile parameters.
+//
+// Multiple shaper instances can share the same shaper profile. Each
+// node has zero or one private shaper (only one node using it) and/or
+// zero, one or several shared shapers (multiple nodes use the same
+// shaper instance).
+//
+// Single rate shapers use a single token bucket. A single rate shaper
+// can be configured by setting the rate of the committed bucket to
+// zero, which effectively disables this bucket. The peak bucket is
+// used to limit the rate and the burst size for the current shaper.
+type ShaperParams struct {
+	// Committed token bucket.
+	Committed TokenBucket
+
+	// Peak token bucket.
+	Peak TokenBucket
+
+	// Signed value to be added to the length of each packet for the
+	// purpose of shaping. Can be used to correct the packet length
+	// with the framing overhead bytes that are also consumed on the
+	// wire (e.g. RTE_TM_ETH_FRAMING_OVERHEAD_FCS).
+	PktLengthAdjust int32
+
+	// When true, shaper rates and sizes are specified in packets per
+	// second and packets, otherwise in bytes per second and bytes.
+	PacketMode bool
+}
+
+// Capabilities is the traffic manager capabilities of an Ethernet
+// device.
+type Capabilities struct {
+	// Maximum number of nodes.
+	NNodesMax uint32
+	// Maximum number of levels (i.e. number of nodes connecting the
+	// root node with any leaf node, including the root and the leaf).
+	NLevelsMax uint32
+	// When true, all the non-leaf nodes (with the exception of the
+	// root node) have identical capability set.
+	NonLeafNodesIdentical bool
+	// When true, all the leaf nodes have identical capability set.
+	LeafNodesIdentical bool
+	// Maximum number of shapers, either private or shared.
+	ShaperNMax uint32
+	// Maximum number of private shapers.
+	ShaperPrivateNMax uint32
+	// Maximum number of private shapers that support dual rate
+	// shaping.
+	ShaperPrivateDualRateNMax uint32
+	// Minimum committed/peak rate for any private shaper.
+	ShaperPrivateRateMin uint64
+	// Maximum committed/peak rate for any private shaper.
+	ShaperPrivateRateMax uint64
+	// Maximum number of shared shapers.
+	ShaperSharedNMax uint32
+	// Maximum number of children nodes.
+	SchedNChildrenMax uint32
+	// Maximum number of supported priority levels.
+	SchedSpNPrioritiesMax uint32
+	// Maximum number of sibling nodes that can have the same priority
+	// at any given time, i.e. maximum size of the WFQ sibling node
+	// group.
+	SchedWfqNChildrenPerGroupMax uint32
+	// Maximum number of different WFQ sibling node groups.
+	SchedWfqNGroupsMax uint32
+	// Maximum WFQ weight.
+	SchedWfqWeightMax uint32
+	// Set of supported statistics counter types.
+	StatsMask StatsType
+}
+
+func (c *Capabilities) fromC(caps *C.struct_rte_tm_capabilities) {
+	*c = Capabilities{
+		NNodesMax:                    uint32(caps.n_nodes_max),
+		NLevelsMax:                   uint32(caps.n_levels_max),
+		NonLeafNodesIdentical:        caps.non_leaf_nodes_identical != 0,
+		LeafNodesIdentical:           caps.leaf_nodes_identical != 0,
+		ShaperNMax:                   uint32(caps.shaper_n_max),
+		ShaperPrivateNMax:            uint32(caps.shaper_private_n_max),
+		ShaperPrivateDualRateNMax:    uint32(caps.shaper_private_dual_rate_n_max),
+		ShaperPrivateRateMin:         uint64(caps.shaper_private_rate_min),
+		ShaperPrivateRateMax:         uint64(caps.shaper_private_rate_max),
+		ShaperSharedNMax:             uint32(caps.shaper_shared_n_max),
+		SchedNChildrenMax:            uint32(caps.sched_n_children_max),
+		SchedSpNPrioritiesMax:        uint32(caps.sched_sp_n_priorities_max),
+		SchedWfqNChildrenPerGroupMax: uint32(caps.sched_wfq_n_children_per_group_max),
+		SchedWfqNGroupsMax:           uint32(caps.sched_wfq_n_groups_max),
+		SchedWfqWeightMax:            uint32(caps.sched_wfq_weight_max),
+		StatsMask:                    StatsType(caps.stats_mask),
+	}
+}
+
+func cerr(e *Error) *C.struct_rte_tm_error {
+	return (*C.struct_rte_tm_error)(e)
+}
+
+func boolToInt(b bool) C.int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// CapabilitiesGet retrieves traffic manager capabilities of the port.
+func CapabilitiesGet(port ethdev.Port, caps *Capabilities, tmErr *Error) error {
+	var c C.struct_rte_tm_capabilities
+	if err := common.IntToErr(C.rte_tm_capabilities_get(C.ushort(port), &c, cerr(tmErr))); err != nil {
+		return err
+	}
+	caps.fromC(&c)
+	return nil
+}
+
+// LeafNodesNum returns the number of leaf nodes of the port. Each leaf
+// node sits on top of a TX queue of the current Ethernet port.
+// Therefore, the set of leaf nodes is predefined, their number is
+// always equal to N (where N is the number of TX queues configured
+// for the current port) and their IDs are 0 .. (N-1).
+func LeafNodesNum(port ethdev.Port, tmErr *Error) (uint32, error) {
+	var n C.uint32_t
+	err := common.IntToErr(C.rte_tm_get_number_of_leaf_nodes(C.ushort(port), &n, cerr(tmErr)))
+	return uint32(n), err
+}
+
+// NodeIsLeaf returns true if node with nodeID is a leaf node.
+func NodeIsLeaf(port ethdev.Port, nodeID uint32, tmErr *Error) (bool, error) {
+	var isLeaf C.int
+	err := common.IntToErr(C.rte_tm_node_type_get(C.ushort(port), C.uint32_t(nodeID), &isLeaf, cerr(tmErr)))
+	return isLeaf != 0, err
+}
+
+// ShaperProfileAdd adds a new shaper profile with ID profileID.
+func ShaperProfileAdd(port ethdev.Port, profileID uint32, params *ShaperParams, tmErr *Error) error {
+	p := C.struct_rte_tm_shaper_params{
+		committed: C.struct_rte_tm_token_bucket{
+			rate: C.uint64_t(params.Committed.Rate),
+			size: C.uint64_t(params.Committed.Size),
+		},
+		peak: C.struct_rte_tm_token_bucket{
+			rate: C.uint64_t(params.Peak.Rate),
+			size: C.uint64_t(params.Peak.Size),
+		},
+		pkt_length_adjust: C.int32_t(params.PktLengthAdjust),
+		packet_mode:       boolToInt(params.PacketMode),
+	}
+
+	return common.IntToErr(C.rte_tm_shaper_profile_add(C.ushort(port),
+		C.uint32_t(profileID), &p, cerr(tmErr)))
+}
+
+// ShaperProfileDelete deletes an existing shaper profile. This
+// operation fails when there is currently at least one user (i.e.
+// shaper) of this shaper profile.
+func ShaperProfileDelete(port ethdev.Port, profileID uint32, tmErr *Error) error {
+	return common.IntToErr(C.rte_tm_shaper_profile_delete(C.ushort(port),
+		C.uint32_t(profileID), cerr(tmErr)))
+}
+
+// SharedShaperAddUpdate creates a new shared shaper with ID
+// sharedShaperID and assigns profile with ID profileID to it. If the
+// shared shaper already exists, its shaper profile is updated.
+func SharedShaperAddUpdate(port ethdev.Port, sharedShaperID, profileID uint32, tmErr *Error) error {
+	return common.IntToErr(C.rte_tm_shared_shaper_add_update(C.ushort(port),
+		C.uint32_t(sharedShaperID), C.uint32_t(profileID), cerr(tmErr)))
+}
+
+// SharedShaperDelete deletes an existing shared shaper. This operation
+// fails when there is currently at least one user (i.e. node) of this
+// shared shaper.
+func SharedShaperDelete(port ethdev.Port, sharedShaperID uint32, tmErr *Error) error {
+	return common.IntToErr(C.rte_tm_shared_shaper_delete(C.ushort(port),
+		C.uint32_t(sharedShaperID), cerr(tmErr)))
+}
+
+type nodeConf struct {
+	shaperProfileID uint32
+	sharedShapers   []uint32
+	nSpPriorities   uint32
+	wredProfileID   uint32
+	statsMask       StatsType
+}
+
+// NodeOption specifies node parameters in NodeAdd.
+type NodeOption struct {
+	f func(*nodeConf)
+}
+
+// OptShaperProfile specifies private shaper profile ID of the node.
+// By default, the node has no private shaper.
+func OptShaperProfile(profileID uint32) NodeOption {
+	return NodeOption{func(c *nodeConf) {
+		c.shaperProfileID = profileID
+	}}
+}
+
+// OptSharedShapers specifies shared shaper IDs the node is attached
+// to.
+func OptSharedShapers(ids ...uint32) NodeOption {
+	return NodeOption{func(c *nodeConf) {
+		c.sharedShapers = append(c.sharedShapers, ids...)
+	}}
+}
+
+// OptSpPriorities specifies number of strict priorities of the
+// children of non-leaf node. Default is 1.
+func OptSpPriorities(n uint32) NodeOption {
+	return NodeOption{func(c *nodeConf) {
+		c.nSpPriorities = n
+	}}
+}
+
+// OptWredProfile specifies WRED profile ID of the leaf node. By
+// default, tail drop congestion management is used.
+func OptWredProfile(profileID uint32) NodeOption {
+	return NodeOption{func(c *nodeConf) {
+		c.wredProfileID = profileID
+	}}
+}
+
+// OptNodeStats specifies statistics counters to be enabled for the
+// node.
+func OptNodeStats(mask StatsType) NodeOption {
+	return NodeOption{func(c *nodeConf) {
+		c.statsMask = mask
+	}}
+}
+
+func makeNodeConf(opts []NodeOption) *nodeConf {
+	c := &nodeConf{
+		shaperProfileID: ShaperProfileIDNone,
+		wredProfileID:   WredProfileIDNone,
+		nSpPriorities:   1,
+	}
+	for i := range opts {
+		opts[i].f(c)
+	}
+	return c
+}
+
+// cParams converts conf to C struct. The returned function should be
+// called to release allocated C memory.
+func (c *nodeConf) cParams(isLeaf bool) (*C.struct_rte_tm_node_params, func()) {
+	p := (*C.struct_rte_tm_node_params)(C.malloc(C.sizeof_struct_rte_tm_node_params))
+	C.memset(unsafe.Pointer(p), 0, C.sizeof_struct_rte_tm_node_params)
+
+	p.shaper_profile_id = C.uint32_t(c.shaperProfileID)
+	p.stats_mask = C.uint64_t(c.statsMask)
+
+	if n := len(c.sharedShapers); n > 0 {
+		sz := C.size_t(n) * C.size_t(unsafe.Sizeof(c.sharedShapers[0]))
+		ids := C.malloc(sz)
+		C.memcpy(ids, unsafe.Pointer(&c.sharedShapers[0]), sz)
+		p.shared_shaper_id = (*C.uint32_t)(ids)
+		p.n_shared_shapers = C.uint32_t(n)
+	}
+
+	if isLeaf {
+		C.set_node_params_leaf(p, C.uint32_t(c.wredProfileID))
+	} else {
+		C.set_node_params_nonleaf(p, C.uint32_t(c.nSpPriorities))
+	}
+
+	return p, func() {
+		C.free(unsafe.Pointer(p.shared_shaper_id))
+		C.free(unsafe.Pointer(p))
+	}
+}
+
+// NodeAdd adds a new node to the hierarchy.
+//
+// This function has to be called for each node added to the
+// hierarchy, before the hierarchy is committed with HierarchyCommit.
+// If parentID is NodeIDNull, the node is the root node. Leaf nodes
+// IDs are predefined as 0 .. (N-1) where N is returned by
+// LeafNodesNum, non-leaf nodes should have IDs other than these.
+//
+// priority is the node priority relative to its sibling nodes, 0 is
+// the highest one. weight is the node weight used by WFQ algorithm
+// among sibling nodes of the same priority. level is the node level
+// in the hierarchy, NodeLevelIDAny may be specified to skip level
+// checking.
+//
+// The error of LeafNodesNum is returned if the number of leaf nodes
+// can't be retrieved.
+func NodeAdd(port ethdev.Port, nodeID, parentID, priority, weight, level uint32, tmErr *Error, opts ...NodeOption) error {
+	n, err := LeafNodesNum(port, tmErr)
+	if err != nil {
+		return err
+	}
+
+	p, free := makeNodeConf(opts).cParams(nodeID < n)
+	defer free()
+
+	return common.IntToErr(C.rte_tm_node_add(C.ushort(port), C.uint32_t(nodeID),
+		C.uint32_t(parentID), C.uint32_t(priority), C.uint32_t(weight),
+		C.uint32_t(level), p, cerr(tmErr)))
+}
+
+// NodeDelete deletes an existing node. This operation fails when
+// this node currently has at least one user (i.e. child node).
+func NodeDelete(port ethdev.Port, nodeID uint32, tmErr *Error) error {
+	return common.IntToErr(C.rte_tm_node_delete(C.ushort(port), C.uint32_t(nodeID), cerr(tmErr)))
+}
+
+// NodeSuspend suspends an existing node. While the node is in
+// suspended state, no packet is scheduled from this node and its
+// descendants.
+func NodeSuspend(port ethdev.Port, nodeID uint32, tmErr *Error) error {
+	return common.IntToErr(C.rte_tm_node_suspend(C.ushort(port), C.uint32_t(nodeID), cerr(tmErr)))
+}
+
+// NodeResume resumes an existing node that is currently suspended.
+func NodeResume(port ethdev.Port, nodeID uint32, tmErr *Error) error {
+	return common.IntToErr(C.rte_tm_node_resume(C.ushort(port), C.uint32_t(nodeID), cerr(tmErr)))
+}
+
+// HierarchyCommit commits the hierarchy.
+//
+// This function is called during the port initialization phase
+// (before the Ethernet port is started) to freeze the start-up
+// hierarchy.
+//
+// If clearOnFail is true then the hierarchy is cleared on failure,
+// i.e. all the nodes, shaper profiles and shared shapers are
+// deleted.
+func HierarchyCommit(port ethdev.Port, clearOnFail bool, tmErr *Error) error {
+	return common.IntToErr(C.rte_tm_hierarchy_commit(C.ushort(port),
+		boolToInt(clearOnFail), cerr(tmErr)))
+}
+
+// NodeParentUpdate updates the parent, priority and weight of an
+// existing node.
+func NodeParentUpdate(port ethdev.Port, nodeID, parentID, priority, weight uint32, tmErr *Error) error {
+	return common.IntToErr(C.rte_tm_node_parent_update(C.ushort(port),
+		C.uint32_t(nodeID), C.uint32_t(parentID), C.uint32_t(priority),
+		C.uint32_t(weight), cerr(tmErr)))
+}
+
+// NodeShaperUpdate updates private shaper profile of an existing
+// node. ShaperProfileIDNone may be specified to remove private shaper.
+func NodeShaperUpdate(port ethdev.Port, nodeID, profileID uint32, tmErr *Error) error {
+	return common.IntToErr(C.rte_tm_node_shaper_update(C.ushort(port),
+		C.uint32_t(nodeID), C.uint32_t(profileID), cerr(tmErr)))
+}
+
+// NodeStatsUpdate updates the set of enabled statistics counters of
+// an existing node.
+func NodeStatsUpdate(port ethdev.Port, nodeID uint32, mask StatsType, tmErr *Error) error {
+	return common.IntToErr(C.rte_tm_node_stats_update(C.ushort(port),
+		C.uint32_t(nodeID), C.uint64_t(mask), cerr(tmErr)))
+}
+
+// NodeStatsRead reads statistics counters of an existing node into s.
+// If clear is true then the counters are cleared after reading.
+//
+// Returns the mask of statistics counters that are valid in s.
+func NodeStatsRead(port ethdev.Port, nodeID uint32, s *NodeStats, clear bool, tmErr *Error) (StatsType, error) {
+	var mask C.uint64_t
+	rc := C.rte_tm_node_stats_read(C.ushort(port), C.uint32_t(nodeID),
+		(*C.struct_rte_tm_node_stats)(unsafe.Pointer(s)), &mask,
+		boolToInt(clear), cerr(tmErr))
+	return StatsType(mask), common.IntToErr(rc)
+}
@@ -0,0 +1,94 @@
+package tm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/yerden/go-dpdk/eal"
+	"github.com/yerden/go-dpdk/ethdev"
+)
+
+func assert(t testing.TB, expected bool, args ...interface{}) {
+	if !expected {
+		t.Helper()
+		t.Fatal(args...)
+	}
+}
+
+// port -> subport -> pipe -> 2 TCs -> 4 queues.
+func sampleHierarchy() *Hierarchy {
+	h := &Hierarchy{}
+	h.Add(
+		Node{ID: 100, ParentID: NodeIDNull, Level: LevelPort},
+		Node{ID: 90, ParentID: 100, Level: LevelSubport, Weight: 1},
+		Node{ID: 80, ParentID: 90, Level: LevelPipe, Weight: 1},
+		Node{ID: 70, ParentID: 80, Level: LevelTC, Weight: 1},
+		Node{ID: 71, ParentID: 80, Level: LevelTC, Priority: 1, Weight: 1},
+	)
+	for i := uint32(0); i < 4; i++ {
+		h.Add(Node{ID: i, ParentID: 70 + i/2, Level: LevelQueue, Weight: 1})
+	}
+	return h
+}
+
+func TestHierarchyValidate(t *testing.T) {
+	h := sampleHierarchy()
+	assert(t, h.Validate(4) == nil)
+
+	var e *NodeError
+	err := h.Validate(2)
+	assert(t, errors.As(err, &e) && e.ID == 2, err)
+	assert(t, errors.Is(err, ErrLeafID), err)
+
+	err = h.Validate(80)
+	assert(t, errors.Is(err, ErrNonLeafID), err)
+
+	h.Add(Node{ID: 4, ParentID: 5, Level: LevelQueue})
+	err = h.Validate(5)
+	assert(t, errors.Is(err, ErrNoParent), err)
+
+	h = sampleHierarchy()
+	h.Nodes[3].Level = LevelQueue
+	err = h.Validate(4)
+	assert(t, errors.Is(err, ErrLevel), err)
+
+	h = sampleHierarchy()
+	h.Add(Node{ID: 101, ParentID: NodeIDNull})
+	err = h.Validate(4)
+	assert(t, errors.Is(err, ErrMultipleRoots), err)
+
+	h = sampleHierarchy()
+	h.Nodes[0].ParentID = 71
+	err = h.Validate(4)
+	assert(t, errors.Is(err, ErrNoRoot), err)
+
+	h = sampleHierarchy()
+	h.Add(Node{ID: 200, ParentID: 201, Level: NodeLevelIDAny},
+		Node{ID: 201, ParentID: 200, Level: NodeLevelIDAny})
+	err = h.Validate(4)
+	assert(t, errors.Is(err, ErrUnreachable), err)
+}
+
+func TestHierarchySorted(t *testing.T) {
+	h := &Hierarchy{}
+	h.Add(
+		Node{ID: 0, ParentID: 11, Level: 2},
+		Node{ID: 11, ParentID: 10, Level: 1},
+		Node{ID: 10, ParentID: NodeIDNull, Level: 0},
+	)
+	assert(t, h.Validate(1) == nil)
+
+	nodes := h.sorted()
+	assert(t, len(nodes) == 3)
+	assert(t, nodes[0].ID == 10 && nodes[1].ID == 11 && nodes[2].ID == 0)
+}
+
+func TestCapabilities(t *testing.T) {
+	eal.InitOnceSafe("test", 4)
+
+	// net_null0, no support for TM
+	var caps Capabilities
+	var e Error
+	err := CapabilitiesGet(ethdev.Port(0), &caps, &e)
+	assert(t, err != nil)
+}
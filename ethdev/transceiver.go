@@ -0,0 +1,211 @@
+package ethdev
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// ErrModuleEEPROM is returned if plugin module EEPROM contents is
+// too short or the type is unknown.
+var ErrModuleEEPROM = errors.New("invalid module EEPROM")
+
+// Transceiver is the plugin module (SFP, QSFP) information parsed
+// from its EEPROM.
+type Transceiver struct {
+	// Module EEPROM specification.
+	Type ModuleType
+
+	// Physical device identifier, e.g. 0x03 for SFP/SFP+/SFP28,
+	// 0x0D for QSFP+, 0x11 for QSFP28.
+	Identifier uint8
+
+	// Vendor name, part number, revision and serial number.
+	VendorName string
+	VendorOUI  [3]byte
+	VendorPN   string
+	VendorRev  string
+	VendorSN   string
+
+	// Vendor's manufacturing date code, YYMMDD and optional lot code.
+	DateCode string
+
+	// Laser wavelength in nanometers.
+	Wavelength float64
+
+	// Digital diagnostics monitoring is available. If false, the
+	// rest of the fields are zero.
+	DDM bool
+
+	// Internal temperature in degrees Celsius.
+	Temperature float64
+
+	// Supply voltage in Volts.
+	Voltage float64
+
+	// Per lane TX bias current in milliamperes.
+	TxBias []float64
+
+	// Per lane TX output power in milliwatts.
+	TxPower []float64
+
+	// Per lane RX input power in milliwatts.
+	RxPower []float64
+}
+
+// SFF-8472 (SFP) memory map, A2h page follows A0h page.
+const (
+	sfpA0Len        = 256
+	sfpVendorName   = 20
+	sfpVendorOUI    = 37
+	sfpVendorPN     = 40
+	sfpVendorRev    = 56
+	sfpWavelength   = 60
+	sfpVendorSN     = 68
+	sfpDateCode     = 84
+	sfpDiagType     = 92
+	sfpDiagDDM      = 1 << 6
+	sfpDiagInternal = 1 << 5
+	sfpA2Temp       = sfpA0Len + 96
+	sfpA2Vcc        = sfpA0Len + 98
+	sfpA2TxBias     = sfpA0Len + 100
+	sfpA2TxPower    = sfpA0Len + 102
+	sfpA2RxPower    = sfpA0Len + 104
+	sfpA2DiagEnd    = sfpA0Len + 106
+	sfpVendorNameSz = 16
+)
+
+// SFF-8436/SFF-8636 (QSFP) memory map, lower page followed by upper
+// page 00h.
+const (
+	qsfpLen        = 256
+	qsfpLanes      = 4
+	qsfpTemp       = 22
+	qsfpVcc        = 26
+	qsfpRxPower    = 34
+	qsfpTxBias     = 42
+	qsfpTxPower    = 50
+	qsfpVendorName = 148
+	qsfpVendorOUI  = 165
+	qsfpVendorPN   = 168
+	qsfpVendorRev  = 184
+	qsfpWavelength = 186
+	qsfpVendorSN   = 196
+	qsfpDateCode   = 212
+)
+
+func sffString(b []byte) string {
+	return string(bytes.TrimRight(b, " \x00"))
+}
+
+// temperature in 1/256 degrees Celsius
+func sffTemp(b []byte) float64 {
+	return float64(int16(binary.BigEndian.Uint16(b))) / 256
+}
+
+// voltage in 100 uV units
+func sffVoltage(b []byte) float64 {
+	return float64(binary.BigEndian.Uint16(b)) / 10000
+}
+
+// bias current in 2 uA units
+func sffBias(b []byte) float64 {
+	return float64(binary.BigEndian.Uint16(b)) * 2 / 1000
+}
+
+// optical power in 0.1 uW units
+func sffPower(b []byte) float64 {
+	return float64(binary.BigEndian.Uint16(b)) / 10000
+}
+
+func sffLanes(b []byte, n int, fn func([]byte) float64) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = fn(b[2*i:])
+	}
+	return out
+}
+
+// ParseTransceiver parses plugin module EEPROM contents retrieved
+// with GetModuleEEPROM. Only internally calibrated diagnostics are
+// supported.
+func ParseTransceiver(typ ModuleType, data []byte) (*Transceiver, error) {
+	switch typ {
+	case ModuleSFF8079, ModuleSFF8472:
+		return parseSFP(typ, data)
+	case ModuleSFF8436, ModuleSFF8636:
+		return parseQSFP(typ, data)
+	}
+	return nil, ErrModuleEEPROM
+}
+
+func parseSFP(typ ModuleType, data []byte) (*Transceiver, error) {
+	if len(data) < sfpA0Len {
+		return nil, ErrModuleEEPROM
+	}
+
+	t := &Transceiver{
+		Type:       typ,
+		Identifier: data[0],
+		VendorName: sffString(data[sfpVendorName : sfpVendorName+sfpVendorNameSz]),
+		VendorPN:   sffString(data[sfpVendorPN:sfpVendorRev]),
+		VendorRev:  sffString(data[sfpVendorRev:sfpWavelength]),
+		Wavelength: float64(binary.BigEndian.Uint16(data[sfpWavelength:])),
+		VendorSN:   sffString(data[sfpVendorSN:sfpDateCode]),
+		DateCode:   sffString(data[sfpDateCode:sfpDiagType]),
+	}
+	copy(t.VendorOUI[:], data[sfpVendorOUI:])
+
+	if typ != ModuleSFF8472 || len(data) < sfpA2DiagEnd {
+		return t, nil
+	}
+
+	// externally calibrated diagnostics are not supported
+	if diag := data[sfpDiagType]; diag&sfpDiagDDM == 0 || diag&sfpDiagInternal == 0 {
+		return t, nil
+	}
+
+	t.DDM = true
+	t.Temperature = sffTemp(data[sfpA2Temp:])
+	t.Voltage = sffVoltage(data[sfpA2Vcc:])
+	t.TxBias = []float64{sffBias(data[sfpA2TxBias:])}
+	t.TxPower = []float64{sffPower(data[sfpA2TxPower:])}
+	t.RxPower = []float64{sffPower(data[sfpA2RxPower:])}
+	return t, nil
+}
+
+func parseQSFP(typ ModuleType, data []byte) (*Transceiver, error) {
+	if len(data) < qsfpLen {
+		return nil, ErrModuleEEPROM
+	}
+
+	t := &Transceiver{
+		Type:        typ,
+		Identifier:  data[0],
+		VendorName:  sffString(data[qsfpVendorName : qsfpVendorName+sfpVendorNameSz]),
+		VendorPN:    sffString(data[qsfpVendorPN:qsfpVendorRev]),
+		VendorRev:   sffString(data[qsfpVendorRev:qsfpWavelength]),
+		Wavelength:  float64(binary.BigEndian.Uint16(data[qsfpWavelength:])) / 20,
+		VendorSN:    sffString(data[qsfpVendorSN:qsfpDateCode]),
+		DateCode:    sffString(data[qsfpDateCode : qsfpDateCode+8]),
+		DDM:         true,
+		Temperature: sffTemp(data[qsfpTemp:]),
+		Voltage:     sffVoltage(data[qsfpVcc:]),
+		TxBias:      sffLanes(data[qsfpTxBias:], qsfpLanes, sffBias),
+		RxPower:     sffLanes(data[qsfpRxPower:], qsfpLanes, sffPower),
+	}
+	copy(t.VendorOUI[:], data[qsfpVendorOUI:])
+
+	// TX power monitoring is defined in SFF-8636 only
+	if typ == ModuleSFF8636 {
+		t.TxPower = sffLanes(data[qsfpTxPower:], qsfpLanes, sffPower)
+	}
+
+	return t, nil
+}
+
+// PowerDBm converts power in milliwatts into dBm.
+func PowerDBm(mw float64) float64 {
+	return 10 * math.Log10(mw)
+}
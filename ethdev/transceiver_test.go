@@ -0,0 +1,102 @@
+package ethdev
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestParseTransceiverSFP(t *testing.T) {
+	data := make([]byte, 512)
+	data[0] = 0x03
+	copy(data[20:], "ACME            ")
+	copy(data[37:], []byte{0x00, 0x1b, 0x21})
+	copy(data[40:], "SFP-10G-SR      ")
+	copy(data[56:], "A1  ")
+	binary.BigEndian.PutUint16(data[60:], 850)
+	copy(data[68:], "SN123456        ")
+	copy(data[84:], "231231  ")
+	data[92] = 0x68
+
+	a2 := data[256:]
+	temp := int16(-10 * 256)
+	binary.BigEndian.PutUint16(a2[96:], uint16(temp))
+	binary.BigEndian.PutUint16(a2[98:], 33000)
+	binary.BigEndian.PutUint16(a2[100:], 3000)
+	binary.BigEndian.PutUint16(a2[102:], 5000)
+	binary.BigEndian.PutUint16(a2[104:], 10000)
+
+	tr, err := ParseTransceiver(ModuleSFF8472, data)
+	assert(t, err == nil, err)
+	assert(t, tr.Identifier == 3)
+	assert(t, tr.VendorName == "ACME", tr.VendorName)
+	assert(t, tr.VendorOUI == [3]byte{0x00, 0x1b, 0x21})
+	assert(t, tr.VendorPN == "SFP-10G-SR", tr.VendorPN)
+	assert(t, tr.VendorRev == "A1", tr.VendorRev)
+	assert(t, tr.VendorSN == "SN123456", tr.VendorSN)
+	assert(t, tr.DateCode == "231231", tr.DateCode)
+	assert(t, tr.Wavelength == 850, tr.Wavelength)
+	assert(t, tr.DDM)
+	assert(t, almostEqual(tr.Temperature, -10), tr.Temperature)
+	assert(t, almostEqual(tr.Voltage, 3.3), tr.Voltage)
+	assert(t, almostEqual(tr.TxBias[0], 6), tr.TxBias)
+	assert(t, almostEqual(tr.TxPower[0], 0.5), tr.TxPower)
+	assert(t, almostEqual(tr.RxPower[0], 1), tr.RxPower)
+	assert(t, almostEqual(PowerDBm(tr.RxPower[0]), 0))
+
+	// externally calibrated diagnostics
+	data[92] = 0x50
+	tr, err = ParseTransceiver(ModuleSFF8472, data)
+	assert(t, err == nil, err)
+	assert(t, !tr.DDM && tr.TxBias == nil)
+	data[92] = 0x68
+
+	// no diagnostics
+	tr, err = ParseTransceiver(ModuleSFF8079, data[:256])
+	assert(t, err == nil, err)
+	assert(t, tr.VendorName == "ACME", tr.VendorName)
+	assert(t, !tr.DDM && tr.TxBias == nil)
+
+	_, err = ParseTransceiver(ModuleSFF8472, data[:100])
+	assert(t, err == ErrModuleEEPROM, err)
+
+	_, err = ParseTransceiver(0, data)
+	assert(t, err == ErrModuleEEPROM, err)
+}
+
+func TestParseTransceiverQSFP(t *testing.T) {
+	data := make([]byte, 256)
+	data[0] = 0x11
+	binary.BigEndian.PutUint16(data[22:], 35*256)
+	binary.BigEndian.PutUint16(data[26:], 32500)
+	for i := 0; i < 4; i++ {
+		binary.BigEndian.PutUint16(data[34+2*i:], uint16(1000*(i+1)))
+		binary.BigEndian.PutUint16(data[42+2*i:], 4000)
+		binary.BigEndian.PutUint16(data[50+2*i:], 8000)
+	}
+	copy(data[148:], "ACME QSFP       ")
+	copy(data[168:], "QSFP-100G-SR4   ")
+	binary.BigEndian.PutUint16(data[186:], 850*20)
+	copy(data[196:], "QSN0001         ")
+
+	tr, err := ParseTransceiver(ModuleSFF8636, data)
+	assert(t, err == nil, err)
+	assert(t, tr.Identifier == 0x11)
+	assert(t, tr.VendorName == "ACME QSFP", tr.VendorName)
+	assert(t, tr.VendorPN == "QSFP-100G-SR4", tr.VendorPN)
+	assert(t, tr.VendorSN == "QSN0001", tr.VendorSN)
+	assert(t, tr.Wavelength == 850, tr.Wavelength)
+	assert(t, almostEqual(tr.Temperature, 35), tr.Temperature)
+	assert(t, almostEqual(tr.Voltage, 3.25), tr.Voltage)
+	assert(t, len(tr.RxPower) == 4 && almostEqual(tr.RxPower[3], 0.4), tr.RxPower)
+	assert(t, len(tr.TxBias) == 4 && almostEqual(tr.TxBias[0], 8), tr.TxBias)
+	assert(t, len(tr.TxPower) == 4 && almostEqual(tr.TxPower[0], 0.8), tr.TxPower)
+
+	tr, err = ParseTransceiver(ModuleSFF8436, data)
+	assert(t, err == nil, err)
+	assert(t, tr.TxPower == nil)
+}
@@ -3,6 +3,7 @@ module github.com/yerden/go-dpdk
 go 1.19
 
 require (
+	github.com/google/gopacket v1.1.19
 	github.com/prometheus/client_golang v1.17.0
 	github.com/stretchr/testify v1.9.0
 	golang.org/x/sys v0.18.0
@@ -0,0 +1,6 @@
+package malloc
+
+/*
+#cgo pkg-config: libdpdk
+*/
+import "C"
@@ -0,0 +1,122 @@
+package malloc
+
+/*
+#include <stdlib.h>
+
+#include <rte_config.h>
+#include <rte_malloc.h>
+#include <rte_memory.h>
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+// External heaps allow to allocate memory with this package, mempool
+// and other DPDK libraries from the memory not managed by DPDK, e.g.
+// hugepages mapped by the application. Each external heap is assigned
+// a unique socket ID which may be used in place of NUMA socket.
+
+// CreateHeap creates new empty external heap. Returns syscall.EEXIST
+// if the heap with the same name already exists.
+func CreateHeap(name string) error {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+
+	if C.rte_malloc_heap_create(cname) < 0 {
+		return err()
+	}
+	return nil
+}
+
+// DestroyHeap destroys empty external heap. Returns syscall.EBUSY if
+// the heap still has memory added.
+func DestroyHeap(name string) error {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+
+	if C.rte_malloc_heap_destroy(cname) < 0 {
+		return err()
+	}
+	return nil
+}
+
+// HeapSocket returns the socket ID of the heap. It may be used with
+// OptSocket to allocate memory from the heap.
+func HeapSocket(name string) (int, error) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+
+	socket := C.rte_malloc_heap_get_socket(cname)
+	if socket < 0 {
+		return 0, err()
+	}
+	return int(socket), nil
+}
+
+// IsHeapSocketExternal tests if the socket ID belongs to an external
+// heap.
+func IsHeapSocketExternal(socket int) bool {
+	return C.rte_malloc_heap_socket_is_external(C.int(socket)) == 1
+}
+
+// HeapAddMemory adds the memory area of length bytes starting at addr
+// to the external heap. The area consists of pages of pageSize bytes
+// and iovas are the IO addresses of the pages. iovas may be nil in
+// which case IO addresses are unknown.
+//
+// The memory is registered in DPDK and may be used by other DPDK
+// processes once attached with HeapAttachMemory.
+func HeapAddMemory(name string, addr unsafe.Pointer, length uintptr, iovas []uint64, pageSize uintptr) error {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+
+	var iova *C.rte_iova_t
+	if len(iovas) > 0 {
+		iova = (*C.rte_iova_t)(unsafe.Pointer(&iovas[0]))
+	}
+
+	if C.rte_malloc_heap_memory_add(cname, addr, C.size_t(length), iova,
+		C.uint(len(iovas)), C.size_t(pageSize)) < 0 {
+		return err()
+	}
+	return nil
+}
+
+// HeapRemoveMemory removes the memory area from the external heap.
+// Returns syscall.EBUSY if the area is in use.
+func HeapRemoveMemory(name string, addr unsafe.Pointer, length uintptr) error {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+
+	if C.rte_malloc_heap_memory_remove(cname, addr, C.size_t(length)) < 0 {
+		return err()
+	}
+	return nil
+}
+
+// HeapAttachMemory attaches the memory area added to the external
+// heap by another process. The memory should be mapped at the same
+// address in this process.
+func HeapAttachMemory(name string, addr unsafe.Pointer, length uintptr) error {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+
+	if C.rte_malloc_heap_memory_attach(cname, addr, C.size_t(length)) < 0 {
+		return err()
+	}
+	return nil
+}
+
+// HeapDetachMemory detaches the memory area attached with
+// HeapAttachMemory.
+func HeapDetachMemory(name string, addr unsafe.Pointer, length uintptr) error {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+
+	if C.rte_malloc_heap_memory_detach(cname, addr, C.size_t(length)) < 0 {
+		return err()
+	}
+	return nil
+}
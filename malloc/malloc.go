@@ -0,0 +1,236 @@
+/*
+Package malloc wraps RTE malloc library.
+
+The memory is allocated from DPDK heaps in hugepages so it is pinned
+and may be shared with other DPDK processes and used for DMA. The
+memory is not managed by Go garbage collector so it should be freed
+explicitly and should not contain Go pointers.
+
+Please refer to DPDK Programmer's Guide for reference and caveats.
+*/
+package malloc
+
+/*
+#include <stdio.h>
+#include <stdlib.h>
+
+#include <rte_config.h>
+#include <rte_malloc.h>
+#include <rte_memory.h>
+*/
+import "C"
+
+import (
+	"io"
+	"syscall"
+	"unsafe"
+
+	"github.com/yerden/go-dpdk/common"
+)
+
+// SocketIDAny specifies that the allocation may be done on any NUMA
+// socket.
+const SocketIDAny = C.SOCKET_ID_ANY
+
+type conf struct {
+	align  C.uint
+	socket C.int
+}
+
+// Option alters allocation behaviour.
+type Option struct {
+	f func(*conf)
+}
+
+// OptAlign specifies the alignment of the allocation. It must be a
+// power of two. Default is 0 meaning the allocation is suitably
+// aligned for any kind of variable, i.e. aligned to a cache line.
+func OptAlign(align uint) Option {
+	return Option{func(c *conf) {
+		c.align = C.uint(align)
+	}}
+}
+
+// OptSocket specifies NUMA socket to allocate memory on. Default is
+// SocketIDAny.
+func OptSocket(socket int) Option {
+	return Option{func(c *conf) {
+		c.socket = C.int(socket)
+	}}
+}
+
+func makeConf(opts []Option) *conf {
+	c := &conf{socket: C.SOCKET_ID_ANY}
+	for i := range opts {
+		opts[i].f(c)
+	}
+	return c
+}
+
+func err(n ...interface{}) error {
+	if len(n) == 0 {
+		return common.RteErrno()
+	}
+
+	return common.IntToErr(n[0])
+}
+
+func ptrOrErr(p unsafe.Pointer) (unsafe.Pointer, error) {
+	if p == nil {
+		return nil, syscall.ENOMEM
+	}
+	return p, nil
+}
+
+// Malloc allocates size bytes of memory from DPDK heap. The memory is
+// not initialized. Returns syscall.ENOMEM if the allocation failed.
+func Malloc(size uintptr, opts ...Option) (unsafe.Pointer, error) {
+	c := makeConf(opts)
+	return ptrOrErr(C.rte_malloc_socket(nil, C.size_t(size), c.align, c.socket))
+}
+
+// Zmalloc allocates size bytes of zeroed memory from DPDK heap. See
+// Malloc.
+func Zmalloc(size uintptr, opts ...Option) (unsafe.Pointer, error) {
+	c := makeConf(opts)
+	return ptrOrErr(C.rte_zmalloc_socket(nil, C.size_t(size), c.align, c.socket))
+}
+
+// Calloc allocates zeroed memory for num objects of size bytes from
+// DPDK heap. See Malloc.
+func Calloc(num, size uintptr, opts ...Option) (unsafe.Pointer, error) {
+	c := makeConf(opts)
+	return ptrOrErr(C.rte_calloc_socket(nil, C.size_t(num), C.size_t(size), c.align, c.socket))
+}
+
+// Realloc resizes the memory pointed to by p to size bytes preserving
+// its contents. If p is nil, it is equivalent to Malloc. On failure p
+// is left untouched and syscall.ENOMEM is returned.
+//
+// If OptSocket is specified and the memory needs to be moved, it is
+// moved to the specified socket. Otherwise the memory may be moved to
+// any socket.
+func Realloc(p unsafe.Pointer, size uintptr, opts ...Option) (unsafe.Pointer, error) {
+	c := makeConf(opts)
+	return ptrOrErr(C.rte_realloc_socket(p, C.size_t(size), c.align, c.socket))
+}
+
+// Free releases the memory allocated from DPDK heap. If p is nil, it
+// does nothing.
+func Free(p unsafe.Pointer) {
+	C.rte_free(p)
+}
+
+// Validate checks the memory allocated from DPDK heap and returns its
+// usable size. Returns syscall.EINVAL if p is nil or, if DPDK is built
+// with RTE_MALLOC_DEBUG, the memory guards are corrupted.
+func Validate(p unsafe.Pointer) (uintptr, error) {
+	var size C.size_t
+	if C.rte_malloc_validate(p, &size) < 0 {
+		return 0, syscall.EINVAL
+	}
+	return uintptr(size), nil
+}
+
+// Virt2IOVA returns IO address of the memory allocated from DPDK
+// heap.
+func Virt2IOVA(p unsafe.Pointer) uint64 {
+	return uint64(C.rte_malloc_virt2iova(p))
+}
+
+// New allocates zeroed object of type T on specified NUMA socket and
+// returns the pointer to it. The alignment of the object is at least
+// the alignment of T. The object should be released with Free.
+//
+// Returns syscall.EINVAL if T may contain Go pointers.
+func New[T any](socket int) (*T, error) {
+	if err := common.CheckNoPointers[T](); err != nil {
+		return nil, err
+	}
+
+	var v T
+	p, e := Zmalloc(unsafe.Sizeof(v), OptAlign(uint(unsafe.Alignof(v))), OptSocket(socket))
+	return (*T)(p), e
+}
+
+// NewSlice allocates zeroed slice of n objects of type T on specified
+// NUMA socket. See New. The slice should be released with FreeSlice.
+//
+// Returns syscall.EINVAL if n is negative or the total size
+// overflows.
+func NewSlice[T any](n int, socket int) ([]T, error) {
+	if err := common.CheckNoPointers[T](); err != nil {
+		return nil, err
+	}
+
+	var v T
+	if size := unsafe.Sizeof(v); n < 0 || (size > 0 && uintptr(n) > ^uintptr(0)/size) {
+		return nil, syscall.EINVAL
+	}
+
+	p, e := Calloc(uintptr(n), unsafe.Sizeof(v), OptAlign(uint(unsafe.Alignof(v))), OptSocket(socket))
+	if e != nil {
+		return nil, e
+	}
+	return unsafe.Slice((*T)(p), n), nil
+}
+
+// FreeSlice releases the slice allocated with NewSlice.
+func FreeSlice[T any](s []T) {
+	if cap(s) > 0 {
+		Free(unsafe.Pointer(&s[:1][0]))
+	}
+}
+
+// SocketStats is the heap statistics of NUMA socket.
+type SocketStats struct {
+	// Total bytes on heap.
+	HeapTotalBytes uint64
+
+	// Total free bytes on heap.
+	HeapFreeBytes uint64
+
+	// Size in bytes of largest free block.
+	GreatestFreeSize uint64
+
+	// Number of free elements on heap.
+	FreeCount uint32
+
+	// Number of allocated elements on heap.
+	AllocCount uint32
+
+	// Total allocated bytes on heap.
+	HeapAllocBytes uint64
+}
+
+// GetSocketStats returns the heap statistics of NUMA socket. Returns
+// syscall.EINVAL if socket is invalid.
+func GetSocketStats(socket int) (SocketStats, error) {
+	var s C.struct_rte_malloc_socket_stats
+	if C.rte_malloc_get_socket_stats(C.int(socket), &s) < 0 {
+		return SocketStats{}, syscall.EINVAL
+	}
+
+	return SocketStats{
+		HeapTotalBytes:   uint64(s.heap_totalsz_bytes),
+		HeapFreeBytes:    uint64(s.heap_freesz_bytes),
+		GreatestFreeSize: uint64(s.greatest_free_size),
+		FreeCount:        uint32(s.free_count),
+		AllocCount:       uint32(s.alloc_count),
+		HeapAllocBytes:   uint64(s.heap_allocsz_bytes),
+	}, nil
+}
+
+// DumpStats writes the statistics of all heaps into w.
+func DumpStats(w io.Writer) error {
+	return common.DumpTo(w, func(fp unsafe.Pointer) {
+		C.rte_malloc_dump_stats((*C.FILE)(fp), nil)
+	})
+}
+
+// DumpHeaps writes the layout of all heaps into w.
+func DumpHeaps(w io.Writer) error {
+	return common.DumpTo(w, func(fp unsafe.Pointer) {
+		C.rte_malloc_dump_heaps((*C.FILE)(fp))
+	})
+}
@@ -0,0 +1,115 @@
+package malloc_test
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"syscall"
+	"testing"
+	"unsafe"
+
+	"github.com/yerden/go-dpdk/common"
+	"github.com/yerden/go-dpdk/eal"
+	"github.com/yerden/go-dpdk/malloc"
+)
+
+type testObj struct {
+	a uint64
+	b [3]uint32
+}
+
+func TestMalloc(t *testing.T) {
+	assert := common.Assert(t, true)
+
+	eal.InitOnceSafe("test", 4)
+
+	err := eal.ExecOnMain(func(ctx *eal.LcoreCtx) {
+		p, err := malloc.Zmalloc(100, malloc.OptAlign(256), malloc.OptSocket(int(eal.SocketID())))
+		assert(p != nil && err == nil, err)
+		assert(uintptr(p)%256 == 0)
+		assert(common.MakeSlice(p, 100)[99] == 0)
+
+		size, err := malloc.Validate(p)
+		assert(size >= 100 && err == nil, size, err)
+
+		p, err = malloc.Realloc(p, 1000)
+		assert(p != nil && err == nil, err)
+		malloc.Free(p)
+
+		_, err = malloc.Validate(nil)
+		assert(err == syscall.EINVAL, err)
+
+		obj, err := malloc.New[testObj](int(eal.SocketID()))
+		assert(obj != nil && err == nil, err)
+		assert(*obj == testObj{})
+		obj.b[2] = 1
+		malloc.Free(unsafe.Pointer(obj))
+
+		s, err := malloc.NewSlice[testObj](10, int(eal.SocketID()))
+		assert(len(s) == 10 && err == nil, err)
+		s[9].a = 1
+		malloc.FreeSlice(s)
+
+		_, err = malloc.NewSlice[testObj](-1, int(eal.SocketID()))
+		assert(err == syscall.EINVAL, err)
+		_, err = malloc.NewSlice[testObj](math.MaxInt, int(eal.SocketID()))
+		assert(err == syscall.EINVAL, err)
+
+		// types with pointers are prohibited
+		_, err = malloc.New[*testObj](int(eal.SocketID()))
+		assert(errors.Is(err, syscall.EINVAL), err)
+		_, err = malloc.NewSlice[string](10, int(eal.SocketID()))
+		assert(errors.Is(err, syscall.EINVAL), err)
+
+		stats, err := malloc.GetSocketStats(int(eal.SocketID()))
+		assert(err == nil && stats.HeapTotalBytes > 0, err, stats)
+		assert(stats.HeapTotalBytes == stats.HeapFreeBytes+stats.HeapAllocBytes, stats)
+
+		_, err = malloc.GetSocketStats(-10)
+		assert(err == syscall.EINVAL, err)
+
+		var b bytes.Buffer
+		assert(malloc.DumpStats(&b) == nil && b.Len() > 0)
+	})
+	assert(err == nil, err)
+}
+
+func TestMallocHeap(t *testing.T) {
+	assert := common.Assert(t, true)
+
+	eal.InitOnceSafe("test", 4)
+
+	const pageSize = 4096
+	const length = 256 * pageSize
+
+	err := eal.ExecOnMain(func(ctx *eal.LcoreCtx) {
+		assert(malloc.CreateHeap("test_heap") == nil)
+		assert(malloc.CreateHeap("test_heap") == syscall.EEXIST)
+
+		socket, err := malloc.HeapSocket("test_heap")
+		assert(err == nil && malloc.IsHeapSocketExternal(socket), socket, err)
+
+		mem, err := syscall.Mmap(-1, 0, length, syscall.PROT_READ|syscall.PROT_WRITE,
+			syscall.MAP_ANONYMOUS|syscall.MAP_PRIVATE)
+		assert(err == nil, err)
+		defer syscall.Munmap(mem)
+
+		addr := unsafe.Pointer(&mem[0])
+		assert(malloc.HeapAddMemory("test_heap", addr, length, nil, pageSize) == nil)
+
+		p, err := malloc.Malloc(1000, malloc.OptSocket(socket))
+		assert(err == nil, err)
+		assert(uintptr(p) >= uintptr(addr) && uintptr(p) < uintptr(addr)+length)
+
+		err = malloc.HeapRemoveMemory("test_heap", addr, length)
+		assert(err == syscall.EBUSY, err)
+		malloc.Free(p)
+
+		assert(malloc.HeapRemoveMemory("test_heap", addr, length) == nil)
+		assert(malloc.DestroyHeap("test_heap") == nil)
+
+		_, err = malloc.HeapSocket("test_heap")
+		assert(err != nil)
+	})
+	assert(err == nil, err)
+}
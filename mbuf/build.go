@@ -0,0 +1,148 @@
+package mbuf
+
+/*
+#include <rte_config.h>
+#include <rte_mbuf.h>
+*/
+import "C"
+
+import (
+	"encoding/binary"
+	"unsafe"
+
+	"github.com/yerden/go-dpdk/packet"
+)
+
+// PrependHeaders encodes headers hdrs into the headroom of the
+// packet, hdrs[0] being the outermost header. Length fields of the
+// headers are filled according to the packet length. Checksums are
+// zeroed, use TxCksum to compute them.
+//
+// Returns ErrNoRoom if there is not enough headroom in the first
+// segment or the error of packet.ValidateHeaders if the headers can't
+// be encoded. The packet is not modified in these cases.
+func (m *Mbuf) PrependHeaders(hdrs ...packet.Header) error {
+	if err := packet.ValidateHeaders(hdrs...); err != nil {
+		return err
+	}
+
+	total := 0
+	for _, h := range hdrs {
+		total += h.Len()
+	}
+
+	if total > int(m.HeadRoomSize()) {
+		return ErrNoRoom
+	}
+
+	for i := len(hdrs) - 1; i >= 0; i-- {
+		h := hdrs[i]
+		n, payloadLen := h.Len(), int(m.PktLen())
+		ptr := C.rte_pktmbuf_prepend(mbuf(m), C.uint16_t(n))
+		h.Put(unsafe.Slice((*byte)(unsafe.Pointer(ptr)), n), payloadLen)
+	}
+
+	return nil
+}
+
+// TxCksum computes the IPv4 header checksum and TCP, UDP or ICMP
+// checksum of the packet which headers are in the first segment.
+// Tunneled packets are processed up to the outer L4 header. IPv6
+// extension headers are not supported.
+//
+// If offload is true, the checksums are not computed in software.
+// Instead, TX offload flags and header lengths are set and L4 checksum
+// field is filled with the pseudo-header checksum as required by PMD.
+// ICMP checksum is always computed in software. The port should be
+// configured with RTE_ETH_TX_OFFLOAD_IPV4_CKSUM,
+// RTE_ETH_TX_OFFLOAD_TCP_CKSUM or RTE_ETH_TX_OFFLOAD_UDP_CKSUM
+// offloads accordingly.
+func (m *Mbuf) TxCksum(offload bool) error {
+	p := packet.Parser{NoTunnels: true}
+	var h packet.Headers
+	if err := p.Parse(m.Data(), &h); err != nil {
+		return err
+	}
+
+	var ip []byte
+	var ipLen int
+	var v4 bool
+	lens := h.Lens()
+	flags := m.OlFlags() &^ (TxIPv4 | TxIPv6 | TxIPCksum | TxL4Mask)
+
+	if ip4 := h.IPv4(); ip4 != nil {
+		ip, ipLen, v4 = ip4, int(ip4.TotalLen()), true
+		ip4[10], ip4[11] = 0, 0
+		if offload {
+			flags |= TxIPv4 | TxIPCksum
+		} else {
+			binary.BigEndian.PutUint16(ip4[10:], IPv4Cksum(ip4))
+		}
+	} else if ip6 := h.IPv6(); ip6 != nil {
+		ip, ipLen = ip6, packet.IPv6Len+int(ip6.PayloadLen())
+		if offload {
+			flags |= TxIPv6
+		}
+	} else {
+		return nil
+	}
+
+	l4off := lens.L2 + lens.L3
+
+	var l4 packet.Layer
+	for _, l := range h.Layers() {
+		if l.Offset == l4off {
+			l4 = l
+			break
+		}
+	}
+
+	var field int
+	var l4flag OlFlags
+	switch l4.Type {
+	case packet.LayerTCP:
+		field, l4flag = 16, TxTCPCksum
+	case packet.LayerUDP:
+		field, l4flag = 6, TxUDPCksum
+	case packet.LayerICMPv4, packet.LayerICMPv6:
+		field = 2
+	default:
+		m.SetOlFlags(flags)
+		return nil
+	}
+
+	b := h.Bytes(l4)
+	b[field], b[field+1] = 0, 0
+
+	var cksum uint16
+	var err error
+	switch {
+	case l4.Type == packet.LayerICMPv4:
+		cksum, err = m.rawCksum(l4off, lens.L2+ipLen-l4off)
+		cksum = ^cksum
+	case offload && l4flag != 0:
+		flags |= l4flag
+		if v4 {
+			cksum = IPv4PhdrCksum(ip, flags)
+		} else {
+			cksum = IPv6PhdrCksum(ip, flags)
+		}
+	default:
+		cksum, err = m.l4Cksum(ip, v4, l4off)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	binary.BigEndian.PutUint16(b[field:], cksum)
+
+	if offload {
+		tx := m.TxOffload()
+		tx.L2Len, tx.L3Len, tx.L4Len = uint16(lens.L2), uint16(lens.L3), uint16(lens.L4)
+		m.SetTxOffload(tx)
+	}
+
+	m.SetOlFlags(flags)
+	return nil
+}
@@ -0,0 +1,96 @@
+package mbuf
+
+import (
+	"encoding/binary"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yerden/go-dpdk/eal"
+	"github.com/yerden/go-dpdk/mempool"
+	"github.com/yerden/go-dpdk/packet"
+)
+
+func buildUDPv4(t *testing.T, m *Mbuf) {
+	assert.NoError(t, m.PktMbufAppend([]byte("hello, world")))
+	assert.NoError(t, m.PrependHeaders(
+		&packet.EthernetHeader{
+			Dst:       net.HardwareAddr{0, 1, 2, 3, 4, 5},
+			Src:       net.HardwareAddr{6, 7, 8, 9, 10, 11},
+			EtherType: packet.EtherTypeIPv4,
+		},
+		&packet.IPv4Header{
+			TTL:      64,
+			Protocol: packet.ProtoUDP,
+			Src:      net.IPv4(10, 0, 0, 1),
+			Dst:      net.IPv4(10, 0, 0, 2),
+		},
+		&packet.UDPHeader{SrcPort: 1000, DstPort: 2000},
+	))
+}
+
+func TestTxCksum(t *testing.T) {
+	eal.InitOnceSafe("test-mbuf", 1)
+
+	mp, err := mempool.CreateMbufPool("test-pool-cksum", 100, 1500)
+	assert.NoError(t, err)
+	defer mp.Free()
+
+	m := PktMbufAlloc(mp)
+	assert.NotNil(t, m)
+	defer m.PktMbufFree()
+
+	buildUDPv4(t, m)
+	assert.Equal(t, uint32(14+20+8+12), m.PktLen())
+
+	var p packet.Parser
+	var h packet.Headers
+	assert.NoError(t, p.Parse(m.Data(), &h))
+	assert.Equal(t, uint16(20+8+12), h.IPv4().TotalLen())
+	assert.Equal(t, uint16(8+12), h.UDP().Length())
+
+	// software
+	assert.NoError(t, m.TxCksum(false))
+	ip, udp := h.IPv4(), h.UDP()
+	assert.Equal(t, uint16(0xffff), RawCksum(ip))
+	cksum := udp.Checksum()
+	assert.NotZero(t, cksum)
+	udp[6], udp[7] = 0, 0
+	assert.Equal(t, cksum, IPv4UDPTCPCksum(ip, udp))
+	assert.Zero(t, m.OlFlags())
+
+	// offload
+	assert.NoError(t, m.TxCksum(true))
+	assert.Equal(t, TxIPv4|TxIPCksum|TxUDPCksum, m.OlFlags())
+	assert.Equal(t, TxOffload{L2Len: 14, L3Len: 20, L4Len: 8}, m.TxOffload())
+	assert.Zero(t, ip.Checksum())
+	assert.Equal(t, IPv4PhdrCksum(ip, m.OlFlags()), udp.Checksum())
+
+	// not enough headroom
+	hdr := &packet.IPv4Header{Options: make([]byte, 40)}
+	assert.Equal(t, ErrNoRoom, m.PrependHeaders(hdr, hdr, hdr, hdr))
+	assert.Equal(t, uint32(14+20+8+12), m.PktLen())
+
+	// options too long
+	hdr = &packet.IPv4Header{Options: make([]byte, packet.MaxOptionsLen+1)}
+	assert.ErrorIs(t, m.PrependHeaders(hdr), syscall.EINVAL)
+	assert.Equal(t, uint32(14+20+8+12), m.PktLen())
+}
+
+func TestRawCksum(t *testing.T) {
+	eal.InitOnceSafe("test-mbuf", 1)
+
+	// example from RFC 1071
+	b := []byte{0x00, 0x01, 0xf2, 0x03, 0xf4, 0xf5, 0xf6, 0xf7}
+	assert.Equal(t, uint16(0xddf2), RawCksum(b))
+	assert.Zero(t, RawCksum(nil))
+
+	ip := make([]byte, 20)
+	ip[0] = 0x45
+	binary.BigEndian.PutUint16(ip[2:], 20)
+	ip[8], ip[9] = 64, 17
+	copy(ip[12:], []byte{192, 168, 0, 1, 192, 168, 0, 199})
+	binary.BigEndian.PutUint16(ip[10:], IPv4Cksum(ip))
+	assert.Equal(t, uint16(0xffff), RawCksum(ip))
+}
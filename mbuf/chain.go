@@ -0,0 +1,244 @@
+package mbuf
+
+/*
+#include <rte_config.h>
+#include <rte_mbuf.h>
+*/
+import "C"
+
+import (
+	"errors"
+	"io"
+	"unsafe"
+
+	"github.com/yerden/go-dpdk/common"
+)
+
+// ErrNoRoom is returned if there is not enough headroom or tailroom
+// in mbuf or the packet is too short for the operation.
+var ErrNoRoom = errors.New("not enough room in mbuf")
+
+// DataLen returns amount of data in this segment.
+func (m *Mbuf) DataLen() uint16 {
+	return uint16(mbuf(m).data_len)
+}
+
+// LastSeg returns the last segment of the packet.
+func (m *Mbuf) LastSeg() *Mbuf {
+	return (*Mbuf)(C.rte_pktmbuf_lastseg(mbuf(m)))
+}
+
+// IsContiguous tests if the packet data is contiguous, i.e. there's
+// only one segment.
+func (m *Mbuf) IsContiguous() bool {
+	return m.NbSegs() == 1
+}
+
+// Segments returns all segments of the packet starting from m.
+func (m *Mbuf) Segments() []*Mbuf {
+	segs := make([]*Mbuf, 0, m.NbSegs())
+	m.ForEachSegment(func(seg *Mbuf) bool {
+		segs = append(segs, seg)
+		return true
+	})
+	return segs
+}
+
+// ForEachSegment calls fn for every segment of the packet starting
+// from m. Iteration stops if fn returns false.
+func (m *Mbuf) ForEachSegment(fn func(*Mbuf) bool) {
+	for seg := m; seg != nil; seg = seg.Next() {
+		if !fn(seg) {
+			return
+		}
+	}
+}
+
+// Chain appends tail packet to m. The nb_segs and pkt_len of m are
+// updated, tail should not be used after the call.
+//
+// Returns syscall.EOVERFLOW if the chain segment limit is exceeded.
+func (m *Mbuf) Chain(tail *Mbuf) error {
+	return common.IntErr(int64(C.rte_pktmbuf_chain(mbuf(m), mbuf(tail))))
+}
+
+// Linearize moves the packet data from all segments into the first
+// one and frees the rest of segments.
+//
+// Returns ErrNoRoom if there is not enough tailroom in the first
+// segment.
+func (m *Mbuf) Linearize() error {
+	if C.rte_pktmbuf_linearize(mbuf(m)) != 0 {
+		return ErrNoRoom
+	}
+	return nil
+}
+
+// Prepend prepends data to the packet. The data is put into the
+// headroom of the first segment.
+//
+// Returns ErrNoRoom if there is not enough headroom in the first
+// segment.
+func (m *Mbuf) Prepend(data []byte) error {
+	if len(data) > int(m.HeadRoomSize()) {
+		return ErrNoRoom
+	}
+
+	ptr := C.rte_pktmbuf_prepend(mbuf(m), C.uint16_t(len(data)))
+	if ptr == nil {
+		return ErrNoRoom
+	}
+
+	copy(unsafe.Slice((*byte)(unsafe.Pointer(ptr)), len(data)), data)
+	return nil
+}
+
+// Adj removes n bytes at the beginning of the packet. The bytes are
+// removed from the first segment only.
+//
+// Returns ErrNoRoom if n is greater than the data length of the first
+// segment.
+func (m *Mbuf) Adj(n uint16) error {
+	if C.rte_pktmbuf_adj(mbuf(m), C.uint16_t(n)) == nil {
+		return ErrNoRoom
+	}
+	return nil
+}
+
+// Trim removes n bytes of data at the end of the packet. The bytes
+// are removed from the last segment only.
+//
+// Returns ErrNoRoom if n is greater than the data length of the last
+// segment.
+func (m *Mbuf) Trim(n uint16) error {
+	if C.rte_pktmbuf_trim(mbuf(m), C.uint16_t(n)) != 0 {
+		return ErrNoRoom
+	}
+	return nil
+}
+
+// PktMbufRead reads length bytes of packet data at offset off. If
+// the data is contiguous in the packet the returned slice points into
+// the mbuf itself and no copy is made. Otherwise the data is copied
+// into buf which is reallocated if it's shorter than length.
+//
+// Returns ErrNoRoom if the requested range exceeds the packet length.
+func (m *Mbuf) PktMbufRead(off, length uint32, buf []byte) ([]byte, error) {
+	if uint64(off)+uint64(length) > uint64(m.PktLen()) {
+		return nil, ErrNoRoom
+	}
+
+	if length == 0 {
+		return buf[:0], nil
+	}
+
+	if uint32(len(buf)) < length {
+		buf = make([]byte, length)
+	}
+
+	p := C.rte_pktmbuf_read(mbuf(m), C.uint32_t(off), C.uint32_t(length), unsafe.Pointer(&buf[0]))
+	if p == nil {
+		return nil, ErrNoRoom
+	}
+
+	if p == unsafe.Pointer(&buf[0]) {
+		return buf[:length], nil
+	}
+
+	return unsafe.Slice((*byte)(p), length), nil
+}
+
+// WriteTo implements io.WriterTo interface. It writes the packet data
+// of all segments into w.
+func (m *Mbuf) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+	var err error
+	m.ForEachSegment(func(seg *Mbuf) bool {
+		var n int
+		n, err = w.Write(seg.Data())
+		total += int64(n)
+		return err == nil
+	})
+	return total, err
+}
+
+// Reader reads the packet data across all segments. It implements
+// io.Reader, io.ByteReader and io.WriterTo interfaces.
+type Reader struct {
+	seg *Mbuf
+	off int
+}
+
+var _ interface {
+	io.Reader
+	io.ByteReader
+	io.WriterTo
+} = (*Reader)(nil)
+
+// NewReader returns new Reader reading the packet m.
+func NewReader(m *Mbuf) *Reader {
+	return &Reader{seg: m}
+}
+
+// Reset resets r to read the packet m.
+func (r *Reader) Reset(m *Mbuf) {
+	r.seg, r.off = m, 0
+}
+
+// rest returns unread data of the current segment skipping empty
+// segments.
+func (r *Reader) rest() []byte {
+	for r.seg != nil {
+		if d := r.seg.Data(); r.off < len(d) {
+			return d[r.off:]
+		}
+		r.seg, r.off = r.seg.Next(), 0
+	}
+	return nil
+}
+
+// Read implements io.Reader interface.
+func (r *Reader) Read(p []byte) (int, error) {
+	var n int
+	for n < len(p) {
+		d := r.rest()
+		if d == nil {
+			break
+		}
+		k := copy(p[n:], d)
+		n += k
+		r.off += k
+	}
+
+	if n == 0 && len(p) > 0 {
+		return 0, io.EOF
+	}
+	return n, nil
+}
+
+// ReadByte implements io.ByteReader interface.
+func (r *Reader) ReadByte() (byte, error) {
+	d := r.rest()
+	if d == nil {
+		return 0, io.EOF
+	}
+	r.off++
+	return d[0], nil
+}
+
+// WriteTo implements io.WriterTo interface.
+func (r *Reader) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+	for {
+		d := r.rest()
+		if d == nil {
+			return total, nil
+		}
+		n, err := w.Write(d)
+		total += int64(n)
+		r.off += n
+		if err != nil {
+			return total, err
+		}
+	}
+}
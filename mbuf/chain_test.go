@@ -0,0 +1,92 @@
+package mbuf
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yerden/go-dpdk/eal"
+	"github.com/yerden/go-dpdk/mempool"
+)
+
+func TestChain(t *testing.T) {
+	eal.InitOnceSafe("test-mbuf", 1)
+
+	mp, err := mempool.CreateMbufPool("test-pool-chain", 100, 1500)
+	assert.NoError(t, err)
+	defer mp.Free()
+
+	var sample []byte
+	segs := make([]*Mbuf, 3)
+	assert.NoError(t, PktMbufAllocBulk(mp, segs))
+	for _, seg := range segs {
+		d := getSample(1000)
+		assert.NoError(t, seg.PktMbufAppend(d))
+		sample = append(sample, d...)
+	}
+
+	head := segs[0]
+	assert.NoError(t, head.Chain(segs[1]))
+	assert.NoError(t, head.Chain(segs[2]))
+
+	assert.Equal(t, uint16(3), head.NbSegs())
+	assert.Equal(t, uint32(3000), head.PktLen())
+	assert.False(t, head.IsContiguous())
+	assert.Len(t, head.Segments(), 3)
+	assert.Equal(t, head.Segments()[2], head.LastSeg())
+
+	// read across segments
+	d, err := head.PktMbufRead(900, 200, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, sample[900:1100], d)
+
+	// read within segment, no copy
+	buf := make([]byte, 100)
+	d, err = head.PktMbufRead(10, 100, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, sample[10:110], d)
+	assert.False(t, &buf[0] == &d[0])
+
+	_, err = head.PktMbufRead(2900, 200, nil)
+	assert.Equal(t, ErrNoRoom, err)
+
+	// io interfaces
+	var out bytes.Buffer
+	n, err := head.WriteTo(&out)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3000), n)
+	assert.Equal(t, sample, out.Bytes())
+
+	all, err := io.ReadAll(NewReader(head))
+	assert.NoError(t, err)
+	assert.Equal(t, sample, all)
+
+	r := NewReader(head)
+	b, err := r.ReadByte()
+	assert.NoError(t, err)
+	assert.Equal(t, sample[0], b)
+	out.Reset()
+	n, err = r.WriteTo(&out)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2999), n)
+	assert.Equal(t, sample[1:], out.Bytes())
+
+	// adjust
+	assert.NoError(t, head.Adj(10))
+	assert.NoError(t, head.Trim(10))
+	sample = sample[10:2990]
+	assert.Equal(t, uint32(len(sample)), head.PktLen())
+	assert.Equal(t, ErrNoRoom, head.Trim(2000))
+
+	hdr := []byte{1, 2, 3, 4}
+	assert.NoError(t, head.Prepend(hdr))
+	sample = append(hdr, sample...)
+	assert.Equal(t, ErrNoRoom, head.Prepend(make([]byte, 1000)))
+
+	// linearize won't fit into 1500 bytes buffer
+	assert.Equal(t, ErrNoRoom, head.Linearize())
+
+	head.PktMbufFree()
+	assert.Zero(t, mp.InUseCount())
+}
@@ -0,0 +1,159 @@
+package mbuf
+
+/*
+#include <errno.h>
+
+#include <rte_config.h>
+#include <rte_version.h>
+#include <rte_mbuf.h>
+#include <rte_ip.h>
+
+static uint16_t go_raw_cksum(const void *buf, size_t len)
+{
+	return rte_raw_cksum(buf, len);
+}
+
+static uint16_t go_ipv4_cksum(const void *ip)
+{
+	return rte_ipv4_cksum(ip);
+}
+
+static uint16_t go_ipv4_udptcp_cksum(const void *ip, const void *l4)
+{
+	return rte_ipv4_udptcp_cksum(ip, l4);
+}
+
+static uint16_t go_ipv6_udptcp_cksum(const void *ip, const void *l4)
+{
+	return rte_ipv6_udptcp_cksum(ip, l4);
+}
+
+static uint16_t go_ipv4_phdr_cksum(const void *ip, uint64_t ol_flags)
+{
+	return rte_ipv4_phdr_cksum(ip, ol_flags);
+}
+
+static uint16_t go_ipv6_phdr_cksum(const void *ip, uint64_t ol_flags)
+{
+	return rte_ipv6_phdr_cksum(ip, ol_flags);
+}
+
+static int go_raw_cksum_mbuf(const struct rte_mbuf *m, uint32_t off,
+		uint32_t len, uint16_t *cksum)
+{
+	return rte_raw_cksum_mbuf(m, off, len, cksum);
+}
+
+static int go_ipv4_udptcp_cksum_mbuf(const struct rte_mbuf *m,
+		const void *ip, uint16_t l4_off, uint16_t *cksum)
+{
+#if RTE_VERSION >= RTE_VERSION_NUM(22, 3, 0, 0)
+	*cksum = rte_ipv4_udptcp_cksum_mbuf(m, ip, l4_off);
+	return 0;
+#else
+	if (m->nb_segs > 1)
+		return -ENOTSUP;
+	*cksum = rte_ipv4_udptcp_cksum(ip, rte_pktmbuf_mtod_offset(m, void *, l4_off));
+	return 0;
+#endif
+}
+
+static int go_ipv6_udptcp_cksum_mbuf(const struct rte_mbuf *m,
+		const void *ip, uint16_t l4_off, uint16_t *cksum)
+{
+#if RTE_VERSION >= RTE_VERSION_NUM(22, 3, 0, 0)
+	*cksum = rte_ipv6_udptcp_cksum_mbuf(m, ip, l4_off);
+	return 0;
+#else
+	if (m->nb_segs > 1)
+		return -ENOTSUP;
+	*cksum = rte_ipv6_udptcp_cksum(ip, rte_pktmbuf_mtod_offset(m, void *, l4_off));
+	return 0;
+#endif
+}
+*/
+import "C"
+
+import (
+	"encoding/binary"
+	"unsafe"
+
+	"github.com/yerden/go-dpdk/common"
+	"github.com/yerden/go-dpdk/packet"
+)
+
+// The checksums are computed by DPDK over native 16-bit words. The
+// result is converted so that it may be stored in the packet with
+// binary.BigEndian.
+func cksumValue(v C.uint16_t) uint16 {
+	var b [2]byte
+	*(*uint16)(unsafe.Pointer(&b[0])) = uint16(v)
+	return binary.BigEndian.Uint16(b[:])
+}
+
+// RawCksum computes the one's complement sum of b, not complemented.
+func RawCksum(b []byte) uint16 {
+	if len(b) == 0 {
+		return 0
+	}
+	return cksumValue(C.go_raw_cksum(unsafe.Pointer(&b[0]), C.size_t(len(b))))
+}
+
+// IPv4Cksum computes the IPv4 header checksum. The checksum field of
+// the header should be zeroed.
+func IPv4Cksum(ip packet.IPv4) uint16 {
+	return cksumValue(C.go_ipv4_cksum(unsafe.Pointer(&ip[0])))
+}
+
+// IPv4UDPTCPCksum computes UDP or TCP checksum over IPv4 pseudo
+// header, L4 header and payload. The checksum field of L4 header
+// should be zeroed. l4 should contain the header and the whole
+// payload as specified by IPv4 total length.
+func IPv4UDPTCPCksum(ip packet.IPv4, l4 []byte) uint16 {
+	return cksumValue(C.go_ipv4_udptcp_cksum(unsafe.Pointer(&ip[0]), unsafe.Pointer(&l4[0])))
+}
+
+// IPv6UDPTCPCksum computes UDP, TCP or ICMPv6 checksum over IPv6
+// pseudo header, L4 header and payload. The checksum field of L4
+// header should be zeroed. l4 should contain the header and the whole
+// payload as specified by IPv6 payload length.
+func IPv6UDPTCPCksum(ip packet.IPv6, l4 []byte) uint16 {
+	return cksumValue(C.go_ipv6_udptcp_cksum(unsafe.Pointer(&ip[0]), unsafe.Pointer(&l4[0])))
+}
+
+// IPv4PhdrCksum computes IPv4 pseudo header checksum as required by
+// PMD for TX L4 checksum offload or TSO. If flags contain TxTCPSeg
+// the length is not included in the checksum.
+func IPv4PhdrCksum(ip packet.IPv4, flags OlFlags) uint16 {
+	return cksumValue(C.go_ipv4_phdr_cksum(unsafe.Pointer(&ip[0]), C.uint64_t(flags)))
+}
+
+// IPv6PhdrCksum computes IPv6 pseudo header checksum as required by
+// PMD for TX L4 checksum offload or TSO. If flags contain TxTCPSeg
+// the length is not included in the checksum.
+func IPv6PhdrCksum(ip packet.IPv6, flags OlFlags) uint16 {
+	return cksumValue(C.go_ipv6_phdr_cksum(unsafe.Pointer(&ip[0]), C.uint64_t(flags)))
+}
+
+// l4Cksum computes L4 checksum of the packet over all segments. Prior
+// to DPDK 22.03 the packet should be contiguous.
+func (m *Mbuf) l4Cksum(ip []byte, v4 bool, l4off int) (uint16, error) {
+	var cksum C.uint16_t
+	var rc C.int
+	if v4 {
+		rc = C.go_ipv4_udptcp_cksum_mbuf(mbuf(m), unsafe.Pointer(&ip[0]), C.uint16_t(l4off), &cksum)
+	} else {
+		rc = C.go_ipv6_udptcp_cksum_mbuf(mbuf(m), unsafe.Pointer(&ip[0]), C.uint16_t(l4off), &cksum)
+	}
+	return cksumValue(cksum), common.IntErr(int64(rc))
+}
+
+// rawCksum computes the one's complement sum of length bytes of the
+// packet data starting at off over all segments.
+func (m *Mbuf) rawCksum(off, length int) (uint16, error) {
+	var cksum C.uint16_t
+	if C.go_raw_cksum_mbuf(mbuf(m), C.uint32_t(off), C.uint32_t(length), &cksum) != 0 {
+		return 0, ErrNoRoom
+	}
+	return cksumValue(cksum), nil
+}
@@ -0,0 +1,196 @@
+package mbuf
+
+/*
+#include <stdio.h>
+
+#include <rte_config.h>
+#include <rte_mbuf.h>
+*/
+import "C"
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"unsafe"
+
+	"github.com/yerden/go-dpdk/common"
+	"github.com/yerden/go-dpdk/packet"
+)
+
+// Errors reported in free debug mode, see SetFreeDebug.
+var (
+	ErrDoubleFree      = errors.New("mbuf double free")
+	ErrRefCntUnderflow = errors.New("mbuf refcnt underflow")
+)
+
+// SanityError is returned if the mbuf fails the sanity check.
+type SanityError struct {
+	Reason string
+}
+
+// Error implements error interface.
+func (e *SanityError) Error() string {
+	return "mbuf sanity check failed: " + e.Reason
+}
+
+// SanityCheck checks the consistency of the mbuf fields. If isHeader
+// is true, m is checked as the first segment of the packet. Returns
+// *SanityError on failure.
+func (m *Mbuf) SanityCheck(isHeader bool) error {
+	var reason *C.char
+	var h C.int
+	if isHeader {
+		h = 1
+	}
+
+	if C.rte_mbuf_check(mbuf(m), h, &reason) != 0 {
+		return &SanityError{C.GoString(reason)}
+	}
+
+	return nil
+}
+
+// Dump writes the description of the mbuf and at most dumpLen bytes
+// of its data into w.
+func (m *Mbuf) Dump(w io.Writer, dumpLen int) error {
+	return common.DumpTo(w, func(fp unsafe.Pointer) {
+		C.rte_pktmbuf_dump((*C.FILE)(fp), mbuf(m), C.uint(dumpLen))
+	})
+}
+
+// HexDump writes the description of the mbuf and the hex dump of its
+// data into w. The data is annotated with the headers decoded by
+// parser p. All segments of the packet are dumped.
+func (m *Mbuf) HexDump(w io.Writer, p *packet.Parser) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "mbuf %p: port=%d pkt_len=%d nb_segs=%d refcnt=%d ol_flags=%#x ptype=%#x\n",
+		m, m.Port(), m.PktLen(), m.NbSegs(), m.RefCntRead(), uint64(m.OlFlags()), m.PacketType())
+
+	data, err := m.PktMbufRead(0, m.PktLen(), nil)
+	if err != nil {
+		return err
+	}
+
+	var h packet.Headers
+	err = p.Parse(data, &h)
+
+	off := 0
+	for _, l := range h.Layers() {
+		hexDumpLayer(&buf, l.Type.String(), data, l.Offset, l.End())
+		off = l.End()
+	}
+
+	if err != nil {
+		fmt.Fprintf(&buf, "decode error: %v\n", err)
+		hexDumpLayer(&buf, "Raw", data, off, len(data))
+	} else if off < len(data) {
+		hexDumpLayer(&buf, packet.LayerPayload.String(), data, off, len(data))
+	}
+
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+func hexDumpLayer(buf *bytes.Buffer, name string, data []byte, start, end int) {
+	fmt.Fprintf(buf, "%s off=%d len=%d\n", name, start, end-start)
+	for off := start; off < end; off += 16 {
+		fmt.Fprintf(buf, "  %04x ", off)
+		for i := off; i < off+16 && i < end; i++ {
+			fmt.Fprintf(buf, " %02x", data[i])
+		}
+		buf.WriteByte('\n')
+	}
+}
+
+type freeDebugger struct {
+	freed DynFlag
+	fn    func(*Mbuf, error)
+}
+
+var freeDebug atomic.Pointer[freeDebugger]
+
+// SetFreeDebug enables debug mode of PktMbufFree and PktMbufFreeBulk
+// if fn is not nil and disables it otherwise. In debug mode every
+// segment of the freed packet is checked with SanityCheck and for
+// refcnt underflow and double free. If the packet is invalid, fn is
+// called with the corresponding error and the packet is not freed.
+//
+// Double free is detected with the dynamic flag set on the segments
+// being returned to the mempool and reset on allocation. Thus, the
+// double free of indirect mbufs or mbufs with external buffers is not
+// detected since their flags are reset on detach.
+//
+// Debug mode slows down the freeing of mbufs and should not be used
+// in production.
+func SetFreeDebug(fn func(m *Mbuf, err error)) error {
+	if fn == nil {
+		freeDebug.Store(nil)
+		return nil
+	}
+
+	flag, err := RegisterDynFlag("go_dpdk_mbuf_dbg_freed")
+	if err != nil {
+		return err
+	}
+
+	freeDebug.Store(&freeDebugger{flag, fn})
+	return nil
+}
+
+func (d *freeDebugger) check(m *Mbuf) error {
+	for seg := m; seg != nil; seg = seg.Next() {
+		if d.freed.Test(seg) {
+			return ErrDoubleFree
+		}
+
+		if seg.RefCntRead() == 0 {
+			return ErrRefCntUnderflow
+		}
+
+		if err := seg.SanityCheck(seg == m); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// prefree checks the packet and marks segments which are about to be
+// returned into mempool. Returns false if the packet should not be
+// freed.
+func (d *freeDebugger) prefree(m *Mbuf) bool {
+	if err := d.check(m); err != nil {
+		d.fn(m, err)
+		return false
+	}
+
+	for seg := m; seg != nil; seg = seg.Next() {
+		if seg.RefCntRead() == 1 {
+			d.freed.Set(seg)
+		}
+	}
+
+	return true
+}
+
+func (d *freeDebugger) prefreeBulk(ms []*Mbuf) []*Mbuf {
+	var out []*Mbuf
+	for i, m := range ms {
+		if d.prefree(m) {
+			if out != nil {
+				out = append(out, m)
+			}
+		} else if out == nil {
+			out = append(make([]*Mbuf, 0, len(ms)), ms[:i]...)
+		}
+	}
+
+	if out == nil {
+		return ms
+	}
+
+	return out
+}
@@ -0,0 +1,94 @@
+package mbuf
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yerden/go-dpdk/eal"
+	"github.com/yerden/go-dpdk/mempool"
+	"github.com/yerden/go-dpdk/packet"
+)
+
+func TestDump(t *testing.T) {
+	eal.InitOnceSafe("test-mbuf", 1)
+
+	mp, err := mempool.CreateMbufPool("test-pool-dump", 100, 1500)
+	assert.NoError(t, err)
+	defer mp.Free()
+
+	m := PktMbufAlloc(mp)
+	assert.NotNil(t, m)
+	defer m.PktMbufFree()
+
+	buildUDPv4(t, m)
+	assert.NoError(t, m.SanityCheck(true))
+
+	var out bytes.Buffer
+	assert.NoError(t, m.Dump(&out, 64))
+	assert.Contains(t, out.String(), "pkt_len=54")
+
+	out.Reset()
+	assert.NoError(t, m.HexDump(&out, &packet.Parser{}))
+	s := out.String()
+	assert.Contains(t, s, "Ethernet off=0 len=14\n  0000  00 01 02 03 04 05 06 07 08 09 0a 0b 08 00\n")
+	assert.Contains(t, s, "IPv4 off=14 len=20\n")
+	assert.Contains(t, s, "UDP off=34 len=8\n")
+	assert.Contains(t, s, "Payload off=42 len=12\n")
+
+	// truncated
+	assert.NoError(t, m.Trim(44))
+	out.Reset()
+	assert.NoError(t, m.HexDump(&out, &packet.Parser{}))
+	assert.Contains(t, out.String(), "decode error: ")
+	assert.Contains(t, out.String(), "Raw off=0 len=10\n")
+
+	// broken mbuf
+	m.RefCntSet(0)
+	assert.IsType(t, &SanityError{}, m.SanityCheck(true))
+	m.RefCntSet(1)
+}
+
+func TestFreeDebug(t *testing.T) {
+	eal.InitOnceSafe("test-mbuf", 1)
+
+	mp, err := mempool.CreateMbufPool("test-pool-free-debug", 100, 1500)
+	assert.NoError(t, err)
+	defer mp.Free()
+
+	var errs []error
+	assert.NoError(t, SetFreeDebug(func(m *Mbuf, err error) {
+		errs = append(errs, err)
+	}))
+	defer SetFreeDebug(nil)
+
+	m := PktMbufAlloc(mp)
+	assert.NotNil(t, m)
+	m.PktMbufFree()
+	assert.Zero(t, mp.InUseCount())
+	assert.Empty(t, errs)
+
+	m.PktMbufFree()
+	assert.Equal(t, []error{ErrDoubleFree}, errs)
+	assert.Zero(t, mp.InUseCount())
+
+	// refcnt underflow
+	errs = nil
+	m = PktMbufAlloc(mp)
+	assert.NotNil(t, m)
+	m.RefCntSet(0)
+	m.PktMbufFree()
+	assert.Equal(t, []error{ErrRefCntUnderflow}, errs)
+	assert.Equal(t, 1, mp.InUseCount())
+	m.RefCntSet(1)
+	m.PktMbufFree()
+	assert.Zero(t, mp.InUseCount())
+
+	// duplicate in bulk
+	errs = nil
+	ms := make([]*Mbuf, 2)
+	assert.NoError(t, PktMbufAllocBulk(mp, ms))
+	PktMbufFreeBulk([]*Mbuf{ms[0], ms[1], ms[0]})
+	assert.Equal(t, []error{ErrDoubleFree}, errs)
+	assert.Zero(t, mp.InUseCount())
+}
@@ -0,0 +1,197 @@
+package mbuf
+
+/*
+#include <errno.h>
+#include <stdlib.h>
+#include <string.h>
+
+#include <rte_config.h>
+#include <rte_errno.h>
+#include <rte_mbuf.h>
+#include <rte_mbuf_dyn.h>
+
+static int go_dynfield_register(const char *name, size_t size, size_t align)
+{
+	struct rte_mbuf_dynfield params;
+
+	memset(&params, 0, sizeof(params));
+	if (strlen(name) >= sizeof(params.name)) {
+		rte_errno = ENAMETOOLONG;
+		return -1;
+	}
+
+	strcpy(params.name, name);
+	params.size = size;
+	params.align = align;
+	return rte_mbuf_dynfield_register(&params);
+}
+
+static int go_dynfield_lookup(const char *name, size_t *size)
+{
+	struct rte_mbuf_dynfield params;
+	int rc = rte_mbuf_dynfield_lookup(name, &params);
+	if (rc >= 0)
+		*size = params.size;
+	return rc;
+}
+
+static int go_dynflag_register(const char *name)
+{
+	struct rte_mbuf_dynflag params;
+
+	memset(&params, 0, sizeof(params));
+	if (strlen(name) >= sizeof(params.name)) {
+		rte_errno = ENAMETOOLONG;
+		return -1;
+	}
+
+	strcpy(params.name, name);
+	return rte_mbuf_dynflag_register(&params);
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"unsafe"
+
+	"github.com/yerden/go-dpdk/common"
+)
+
+// ErrDynFieldSize is returned if the dynamic field registered under
+// the name has different size than requested.
+var ErrDynFieldSize = errors.New("dynamic field size mismatch")
+
+// DynField is the typed accessor to the dynamic mbuf field of type
+// T. T must not contain Go pointers since mbufs reside in C memory.
+type DynField[T any] struct {
+	offset uintptr
+}
+
+// RegisterDynField reserves the room in mbuf for the dynamic field of
+// type T with the given name. The size and alignment of the field are
+// those of T. If the field with the same name, size and alignment is
+// already registered, its offset is reused.
+//
+// The name should be unique and prefixed with the application or
+// library name, e.g. "app_flow_id".
+//
+// Returns syscall.EINVAL if T may contain Go pointers.
+func RegisterDynField[T any](name string) (*DynField[T], error) {
+	if err := common.CheckNoPointers[T](); err != nil {
+		return nil, err
+	}
+
+	var v T
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+
+	n := C.go_dynfield_register(cname, C.size_t(unsafe.Sizeof(v)), C.size_t(unsafe.Alignof(v)))
+	if n < 0 {
+		return nil, common.RteErrno()
+	}
+
+	return &DynField[T]{uintptr(n)}, nil
+}
+
+// LookupDynField looks up the dynamic field of type T registered
+// under the name, e.g. by other library or process.
+//
+// Returns syscall.ENOENT if the field is not registered,
+// ErrDynFieldSize if its size is different from the size of T and
+// syscall.EINVAL if T may contain Go pointers.
+func LookupDynField[T any](name string) (*DynField[T], error) {
+	if err := common.CheckNoPointers[T](); err != nil {
+		return nil, err
+	}
+
+	var v T
+	var size C.size_t
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+
+	n := C.go_dynfield_lookup(cname, &size)
+	if n < 0 {
+		return nil, common.RteErrno()
+	}
+
+	if uintptr(size) != unsafe.Sizeof(v) {
+		return nil, ErrDynFieldSize
+	}
+
+	return &DynField[T]{uintptr(n)}, nil
+}
+
+// Offset returns the offset of the field in mbuf.
+func (f *DynField[T]) Offset() uintptr {
+	return f.offset
+}
+
+// Ptr returns pointer to the field in m.
+func (f *DynField[T]) Ptr(m *Mbuf) *T {
+	return (*T)(unsafe.Add(unsafe.Pointer(m), f.offset))
+}
+
+// Get returns the value of the field in m.
+func (f *DynField[T]) Get(m *Mbuf) T {
+	return *f.Ptr(m)
+}
+
+// Set sets the value of the field in m.
+func (f *DynField[T]) Set(m *Mbuf, v T) {
+	*f.Ptr(m) = v
+}
+
+// DynFlag is the dynamic mbuf offload flag, i.e. its bit number in
+// mbuf's ol_flags.
+type DynFlag uint
+
+// RegisterDynFlag reserves a bit in mbuf's ol_flags for the dynamic
+// flag with the given name. If the flag with the same name is already
+// registered, its bit number is reused.
+func RegisterDynFlag(name string) (DynFlag, error) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+
+	n := C.go_dynflag_register(cname)
+	if n < 0 {
+		return 0, common.RteErrno()
+	}
+
+	return DynFlag(n), nil
+}
+
+// LookupDynFlag looks up the dynamic flag registered under the name.
+//
+// Returns syscall.ENOENT if the flag is not registered.
+func LookupDynFlag(name string) (DynFlag, error) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+
+	n := C.rte_mbuf_dynflag_lookup(cname, nil)
+	if n < 0 {
+		return 0, common.RteErrno()
+	}
+
+	return DynFlag(n), nil
+}
+
+// Mask returns the flag as the ol_flags mask.
+func (f DynFlag) Mask() OlFlags {
+	return 1 << f
+}
+
+// Set sets the flag in m.
+func (f DynFlag) Set(m *Mbuf) {
+	mbuf(m).ol_flags |= C.uint64_t(f.Mask())
+}
+
+// Clear clears the flag in m.
+func (f DynFlag) Clear(m *Mbuf) {
+	mbuf(m).ol_flags &^= C.uint64_t(f.Mask())
+}
+
+// Test returns true if the flag is set in m.
+func (f DynFlag) Test(m *Mbuf) bool {
+	return m.OlFlags()&f.Mask() != 0
+}
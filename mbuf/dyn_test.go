@@ -0,0 +1,72 @@
+package mbuf
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yerden/go-dpdk/eal"
+	"github.com/yerden/go-dpdk/mempool"
+)
+
+type flowMeta struct {
+	ID    uint32
+	Class uint16
+	Mark  uint16
+}
+
+func TestDynField(t *testing.T) {
+	eal.InitOnceSafe("test-mbuf", 1)
+
+	mp, err := mempool.CreateMbufPool("test-pool-dyn", 100, 1500)
+	assert.NoError(t, err)
+	defer mp.Free()
+
+	field, err := RegisterDynField[flowMeta]("test_flow_meta")
+	assert.NoError(t, err)
+
+	// repeated registration yields the same offset
+	other, err := RegisterDynField[flowMeta]("test_flow_meta")
+	assert.NoError(t, err)
+	assert.Equal(t, field.Offset(), other.Offset())
+
+	other, err = LookupDynField[flowMeta]("test_flow_meta")
+	assert.NoError(t, err)
+	assert.Equal(t, field.Offset(), other.Offset())
+
+	_, err = LookupDynField[uint64]("test_flow_meta")
+	assert.Equal(t, ErrDynFieldSize, err)
+
+	_, err = LookupDynField[uint64]("test_no_such_field")
+	assert.Equal(t, syscall.ENOENT, err)
+
+	// types with pointers are prohibited
+	_, err = RegisterDynField[*flowMeta]("test_flow_meta_ptr")
+	assert.ErrorIs(t, err, syscall.EINVAL)
+	_, err = LookupDynField[[]byte]("test_flow_meta")
+	assert.ErrorIs(t, err, syscall.EINVAL)
+
+	m := PktMbufAlloc(mp)
+	assert.NotNil(t, m)
+	defer m.PktMbufFree()
+
+	v := flowMeta{ID: 1, Class: 2, Mark: 3}
+	field.Set(m, v)
+	assert.Equal(t, v, field.Get(m))
+	field.Ptr(m).Mark = 4
+	assert.Equal(t, uint16(4), field.Get(m).Mark)
+
+	flag, err := RegisterDynFlag("test_flow_flag")
+	assert.NoError(t, err)
+
+	f, err := LookupDynFlag("test_flow_flag")
+	assert.NoError(t, err)
+	assert.Equal(t, flag, f)
+
+	assert.False(t, flag.Test(m))
+	flag.Set(m)
+	assert.True(t, flag.Test(m))
+	assert.NotZero(t, m.OlFlags()&flag.Mask())
+	flag.Clear(m)
+	assert.False(t, flag.Test(m))
+}
@@ -0,0 +1,229 @@
+package mbuf
+
+/*
+#include <errno.h>
+#include <stdlib.h>
+
+#include <rte_config.h>
+#include <rte_mbuf.h>
+
+extern void goExtBufFree(uint64_t);
+
+struct go_extbuf {
+	struct rte_mbuf_ext_shared_info shinfo;
+	uint64_t id;
+};
+
+static void go_extbuf_free_cb(void *addr, void *opaque)
+{
+	struct go_extbuf *eb = opaque;
+	(void)addr;
+	goExtBufFree(eb->id);
+	free(eb);
+}
+
+static struct go_extbuf *go_extbuf_new(uint64_t id)
+{
+	struct go_extbuf *eb = calloc(1, sizeof(*eb));
+	if (eb == NULL)
+		return NULL;
+
+	eb->id = id;
+	eb->shinfo.free_cb = go_extbuf_free_cb;
+	eb->shinfo.fcb_opaque = eb;
+	rte_mbuf_ext_refcnt_set(&eb->shinfo, 1);
+	return eb;
+}
+
+static uint16_t go_extbuf_refcnt(struct go_extbuf *eb)
+{
+	return rte_mbuf_ext_refcnt_read(&eb->shinfo);
+}
+
+static void go_extbuf_release(struct go_extbuf *eb)
+{
+	if (rte_mbuf_ext_refcnt_update(&eb->shinfo, -1) == 0)
+		go_extbuf_free_cb(NULL, eb);
+}
+
+static int go_attach_extbuf(struct rte_mbuf *m, void *addr, rte_iova_t iova,
+		uint16_t len, struct go_extbuf *eb)
+{
+	if (!RTE_MBUF_DIRECT(m) || m->next != NULL)
+		return -EBUSY;
+
+	rte_mbuf_ext_refcnt_update(&eb->shinfo, 1);
+	rte_pktmbuf_attach_extbuf(m, addr, iova, len, &eb->shinfo);
+	m->data_len = len;
+	m->pkt_len = len;
+	return 0;
+}
+
+static int go_attach(struct rte_mbuf *mi, struct rte_mbuf *m)
+{
+	if (!RTE_MBUF_DIRECT(mi) || mi->next != NULL)
+		return -EBUSY;
+
+	rte_pktmbuf_attach(mi, m);
+	return 0;
+}
+*/
+import "C"
+
+import (
+	"math"
+	"sync/atomic"
+	"syscall"
+	"unsafe"
+
+	"github.com/yerden/go-dpdk/common"
+)
+
+// BadIOVA is the IO address of the buffer which is not known to DPDK.
+const BadIOVA = ^uint64(0)
+
+const (
+	// Indirect is set if the mbuf is attached to another mbuf.
+	Indirect OlFlags = C.RTE_MBUF_F_INDIRECT
+
+	// External is set if the mbuf is attached to external buffer.
+	External OlFlags = C.RTE_MBUF_F_EXTERNAL
+)
+
+var extBufs = common.NewRegistryMap()
+
+// ExtBuf is the external buffer which may be attached to mbufs
+// instead of their own data buffers. The buffer is shared by all
+// attached mbufs and its reference counter is managed by DPDK. When
+// the last mbuf is freed and the owner of ExtBuf has released it, the
+// free callback is invoked.
+//
+// The buffer should not be moved by Go runtime, i.e. it should reside
+// in DPDK memory (e.g. memzone) or C memory, or be pinned if it's
+// allocated by Go.
+type ExtBuf struct {
+	eb   *C.struct_go_extbuf
+	id   common.ObjectID
+	buf  []byte
+	iova uint64
+	fn   func([]byte)
+
+	// released is set once the reference held by ExtBuf is dropped.
+	// eb may be freed by DPDK at any moment after that.
+	released uint32
+}
+
+// NewExtBuf creates external buffer over buf. iova is the IO address
+// of buf which should be contiguous in IO address space, or BadIOVA if
+// the buffer is not used for DMA. fn is invoked with buf when the
+// buffer is no longer referenced and may be nil. fn is called from
+// the thread which freed the last mbuf so it should not block.
+//
+// The ExtBuf holds one reference on the buffer which should be
+// dropped with Release when no more mbufs are to be attached.
+func NewExtBuf(buf []byte, iova uint64, fn func([]byte)) (*ExtBuf, error) {
+	eb := &ExtBuf{buf: buf, iova: iova, fn: fn}
+	eb.id = extBufs.Create(eb)
+	if eb.eb = C.go_extbuf_new(C.uint64_t(eb.id)); eb.eb == nil {
+		extBufs.Delete(eb.id)
+		return nil, syscall.ENOMEM
+	}
+	return eb, nil
+}
+
+// Bytes returns the buffer.
+func (eb *ExtBuf) Bytes() []byte {
+	return eb.buf
+}
+
+// IOVA returns the IO address of the buffer.
+func (eb *ExtBuf) IOVA() uint64 {
+	return eb.iova
+}
+
+func (eb *ExtBuf) isReleased() bool {
+	return atomic.LoadUint32(&eb.released) != 0
+}
+
+// RefCnt returns the number of references to the buffer including
+// the one held by ExtBuf itself. It returns 0 once ExtBuf is released
+// since the buffer may be freed at any moment.
+func (eb *ExtBuf) RefCnt() uint16 {
+	if eb.isReleased() {
+		return 0
+	}
+	return uint16(C.go_extbuf_refcnt(eb.eb))
+}
+
+// Release drops the reference held by ExtBuf. No mbufs may be
+// attached to the buffer after that. If no mbufs are attached the
+// free callback is invoked immediately. Subsequent calls do nothing.
+func (eb *ExtBuf) Release() {
+	if atomic.CompareAndSwapUint32(&eb.released, 0, 1) {
+		C.go_extbuf_release(eb.eb)
+	}
+}
+
+// AttachExtBuf attaches the region of external buffer eb starting at
+// off with specified length to m. The region becomes the packet data
+// of m. m should be direct and consist of a single segment, otherwise
+// syscall.EBUSY is returned. The length should be positive and not
+// exceed math.MaxUint16. syscall.EINVAL is returned if the region is
+// invalid or eb is released.
+//
+// m is detached from the buffer once it's freed.
+func (m *Mbuf) AttachExtBuf(eb *ExtBuf, off, length int) error {
+	if off < 0 || length <= 0 || off+length > len(eb.buf) || length > math.MaxUint16 {
+		return syscall.EINVAL
+	}
+
+	if eb.isReleased() {
+		return syscall.EINVAL
+	}
+
+	addr := unsafe.Pointer(&eb.buf[off])
+
+	iova := eb.iova
+	if iova != BadIOVA {
+		iova += uint64(off)
+	}
+
+	return common.IntErr(int64(C.go_attach_extbuf(mbuf(m), addr,
+		C.rte_iova_t(iova), C.uint16_t(length), eb.eb)))
+}
+
+// Attach attaches m to the data buffer of md so that the data of m is
+// the same as of md. If md owns its data buffer or is indirect, m
+// becomes the indirect mbuf attached to the direct mbuf of md and the
+// reference counter of the latter is incremented. If md has external
+// buffer, m is attached to the same external buffer, i.e. HasExtBuf
+// is true for m, and the reference counter of the buffer's shared info
+// is incremented instead.
+//
+// m should be direct and consist of a single segment, otherwise
+// syscall.EBUSY is returned.
+func (m *Mbuf) Attach(md *Mbuf) error {
+	return common.IntErr(int64(C.go_attach(mbuf(m), mbuf(md))))
+}
+
+// Detach detaches m from the indirect or external buffer and restores
+// its own data buffer. The reference counter of the buffer is
+// decremented and the buffer is freed if it's no longer referenced.
+func (m *Mbuf) Detach() {
+	C.rte_pktmbuf_detach(mbuf(m))
+}
+
+// IsDirect returns true if m owns its data buffer.
+func (m *Mbuf) IsDirect() bool {
+	return m.OlFlags()&(Indirect|External) == 0
+}
+
+// IsIndirect returns true if m is attached to another mbuf.
+func (m *Mbuf) IsIndirect() bool {
+	return m.OlFlags()&Indirect != 0
+}
+
+// HasExtBuf returns true if m is attached to external buffer.
+func (m *Mbuf) HasExtBuf() bool {
+	return m.OlFlags()&External != 0
+}
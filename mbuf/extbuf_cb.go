@@ -0,0 +1,22 @@
+package mbuf
+
+/*
+#include <stdint.h>
+*/
+import "C"
+
+import (
+	"github.com/yerden/go-dpdk/common"
+)
+
+// goExtBufFree is invoked by DPDK when the external buffer is no
+// longer referenced. The C part of ExtBuf is freed by the caller.
+//
+//export goExtBufFree
+func goExtBufFree(id C.uint64_t) {
+	eb := extBufs.Read(common.ObjectID(id)).(*ExtBuf)
+	extBufs.Delete(eb.id)
+	if eb.fn != nil {
+		eb.fn(eb.buf)
+	}
+}
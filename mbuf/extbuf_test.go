@@ -0,0 +1,125 @@
+package mbuf
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yerden/go-dpdk/eal"
+	"github.com/yerden/go-dpdk/mempool"
+	"github.com/yerden/go-dpdk/memzone"
+)
+
+func TestExtBuf(t *testing.T) {
+	eal.InitOnceSafe("test-mbuf", 1)
+
+	mp, err := mempool.CreateMbufPool("test-pool-extbuf", 100, 1500)
+	assert.NoError(t, err)
+	defer mp.Free()
+
+	mz, err := memzone.Reserve("test-mz-extbuf", 8192)
+	assert.NoError(t, err)
+	defer mz.Free()
+
+	sample := getSample(8192)
+	copy(mz.Bytes(), sample)
+
+	var freed int
+	eb, err := NewExtBuf(mz.Bytes(), mz.IOVA(), func(b []byte) {
+		assert.Equal(t, mz.Bytes(), b)
+		freed++
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(1), eb.RefCnt())
+
+	ms := make([]*Mbuf, 2)
+	assert.NoError(t, PktMbufAllocBulk(mp, ms))
+	assert.NoError(t, ms[0].AttachExtBuf(eb, 0, 4000))
+	assert.NoError(t, ms[1].AttachExtBuf(eb, 4000, 4192))
+	assert.Equal(t, uint16(3), eb.RefCnt())
+
+	assert.True(t, ms[0].HasExtBuf())
+	assert.False(t, ms[0].IsDirect())
+	assert.Equal(t, sample[:4000], ms[0].Data())
+	assert.Equal(t, sample[4000:], ms[1].Data())
+	assert.Equal(t, uint32(4192), ms[1].PktLen())
+
+	// already attached
+	assert.Error(t, ms[0].AttachExtBuf(eb, 0, 100))
+
+	// out of bounds
+	m := PktMbufAlloc(mp)
+	assert.NotNil(t, m)
+	assert.Error(t, m.AttachExtBuf(eb, 4000, 5000))
+
+	// empty region
+	assert.Equal(t, syscall.EINVAL, m.AttachExtBuf(eb, 0, 0))
+
+	// attached mbuf shares the external buffer
+	assert.NoError(t, m.Attach(ms[0]))
+	assert.True(t, m.HasExtBuf())
+	assert.False(t, m.IsIndirect())
+	assert.Equal(t, uint16(4), eb.RefCnt())
+	assert.Equal(t, sample[:4000], m.Data())
+
+	eb.Release()
+	PktMbufFreeBulk(ms)
+	assert.Equal(t, 0, freed)
+
+	m.PktMbufFree()
+	assert.Equal(t, 1, freed)
+}
+
+func TestExtBufRelease(t *testing.T) {
+	eal.InitOnceSafe("test-mbuf", 1)
+
+	var freed int
+	eb, err := NewExtBuf(make([]byte, 100), BadIOVA, func([]byte) {
+		freed++
+	})
+	assert.NoError(t, err)
+	eb.Release()
+	assert.Equal(t, 1, freed)
+
+	// released buffer is not accessible
+	mp, err := mempool.CreateMbufPool("test-pool-extbuf-release", 10, 1500)
+	assert.NoError(t, err)
+	defer mp.Free()
+
+	m := PktMbufAlloc(mp)
+	assert.NotNil(t, m)
+	defer m.PktMbufFree()
+
+	assert.Equal(t, uint16(0), eb.RefCnt())
+	assert.Equal(t, syscall.EINVAL, m.AttachExtBuf(eb, 0, 10))
+	eb.Release()
+	assert.Equal(t, 1, freed)
+}
+
+func TestIndirect(t *testing.T) {
+	eal.InitOnceSafe("test-mbuf", 1)
+
+	mp, err := mempool.CreateMbufPool("test-pool-indirect", 100, 1500)
+	assert.NoError(t, err)
+	defer mp.Free()
+
+	md := PktMbufAlloc(mp)
+	assert.NotNil(t, md)
+	sample := getSample(1000)
+	assert.NoError(t, md.PktMbufAppend(sample))
+
+	mi := PktMbufAlloc(mp)
+	assert.NotNil(t, mi)
+	assert.NoError(t, mi.Attach(md))
+	assert.True(t, mi.IsIndirect())
+	assert.True(t, md.IsDirect())
+	assert.Equal(t, uint16(2), md.RefCntRead())
+	assert.Equal(t, sample, mi.Data())
+
+	mi.Detach()
+	assert.True(t, mi.IsDirect())
+	assert.Equal(t, uint16(1), md.RefCntRead())
+
+	mi.PktMbufFree()
+	md.PktMbufFree()
+}
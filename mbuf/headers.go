@@ -0,0 +1,25 @@
+package mbuf
+
+import (
+	"github.com/yerden/go-dpdk/packet"
+)
+
+// ParseHeaders decodes headers in the first segment of the packet
+// into h using parser p. On success, header lengths needed for TX
+// offloads are set in the mbuf, see SetTxOffload. Other TX offload
+// fields, e.g. TSOSegSz, are preserved.
+func (m *Mbuf) ParseHeaders(p *packet.Parser, h *packet.Headers) error {
+	if err := p.Parse(m.Data(), h); err != nil {
+		return err
+	}
+
+	lens := h.Lens()
+	tx := m.TxOffload()
+	tx.L2Len = uint16(lens.L2)
+	tx.L3Len = uint16(lens.L3)
+	tx.L4Len = uint16(lens.L4)
+	tx.OuterL2Len = uint16(lens.OuterL2)
+	tx.OuterL3Len = uint16(lens.OuterL3)
+	m.SetTxOffload(tx)
+	return nil
+}
@@ -79,6 +79,9 @@ func mp(m *mempool.Mempool) *C.struct_rte_mempool {
 // PktMbufFree returns this mbuf into its originating mempool along
 // with all its segments.
 func (m *Mbuf) PktMbufFree() {
+	if d := freeDebug.Load(); d != nil && !d.prefree(m) {
+		return
+	}
 	C.rte_pktmbuf_free(mbuf(m))
 }
 
@@ -108,6 +111,9 @@ func PktMbufAllocBulk(p *mempool.Mempool, ms []*Mbuf) error {
 
 // PktMbufReset frees a bulk of packet mbufs back into their original mempools.
 func PktMbufFreeBulk(ms []*Mbuf) {
+	if d := freeDebug.Load(); d != nil {
+		ms = d.prefreeBulk(ms)
+	}
 	C.free_bulk(mbufs(ms), C.uint(len(ms)))
 }
 
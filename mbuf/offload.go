@@ -0,0 +1,302 @@
+package mbuf
+
+/*
+#include <stdint.h>
+#include <rte_config.h>
+#include <rte_mbuf.h>
+#include <rte_mbuf_dyn.h>
+
+struct go_tx_offload {
+	uint16_t l2_len;
+	uint16_t l3_len;
+	uint16_t l4_len;
+	uint16_t tso_segsz;
+	uint16_t outer_l3_len;
+	uint16_t outer_l2_len;
+};
+
+static void get_tx_offload(const struct rte_mbuf *m, struct go_tx_offload *tx)
+{
+	tx->l2_len = m->l2_len;
+	tx->l3_len = m->l3_len;
+	tx->l4_len = m->l4_len;
+	tx->tso_segsz = m->tso_segsz;
+	tx->outer_l3_len = m->outer_l3_len;
+	tx->outer_l2_len = m->outer_l2_len;
+}
+
+static void set_tx_offload(struct rte_mbuf *m, const struct go_tx_offload *tx)
+{
+	m->l2_len = tx->l2_len;
+	m->l3_len = tx->l3_len;
+	m->l4_len = tx->l4_len;
+	m->tso_segsz = tx->tso_segsz;
+	m->outer_l3_len = tx->outer_l3_len;
+	m->outer_l2_len = tx->outer_l2_len;
+}
+
+enum {
+	MBUF_PTYPE_OFF = offsetof(struct rte_mbuf, packet_type),
+	MBUF_FDIR_HI_OFF = offsetof(struct rte_mbuf, hash.fdir.hi),
+};
+*/
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/yerden/go-dpdk/common"
+)
+
+// OlFlags is the offload features flags of an mbuf. RX flags are set
+// by PMD on receive, TX flags are set by application to request
+// offloads from PMD.
+type OlFlags uint64
+
+// RX offload flags.
+const (
+	RxVLAN             OlFlags = C.RTE_MBUF_F_RX_VLAN
+	RxRSSHash          OlFlags = C.RTE_MBUF_F_RX_RSS_HASH
+	RxFDIR             OlFlags = C.RTE_MBUF_F_RX_FDIR
+	RxOuterIPCksumBad  OlFlags = C.RTE_MBUF_F_RX_OUTER_IP_CKSUM_BAD
+	RxVLANStripped     OlFlags = C.RTE_MBUF_F_RX_VLAN_STRIPPED
+	RxIEEE1588PTP      OlFlags = C.RTE_MBUF_F_RX_IEEE1588_PTP
+	RxIEEE1588TMST     OlFlags = C.RTE_MBUF_F_RX_IEEE1588_TMST
+	RxFDIRID           OlFlags = C.RTE_MBUF_F_RX_FDIR_ID
+	RxFDIRFlx          OlFlags = C.RTE_MBUF_F_RX_FDIR_FLX
+	RxQinQStripped     OlFlags = C.RTE_MBUF_F_RX_QINQ_STRIPPED
+	RxLRO              OlFlags = C.RTE_MBUF_F_RX_LRO
+	RxSecOffload       OlFlags = C.RTE_MBUF_F_RX_SEC_OFFLOAD
+	RxSecOffloadFailed OlFlags = C.RTE_MBUF_F_RX_SEC_OFFLOAD_FAILED
+	RxQinQ             OlFlags = C.RTE_MBUF_F_RX_QINQ
+
+	RxIPCksumMask    OlFlags = C.RTE_MBUF_F_RX_IP_CKSUM_MASK
+	RxIPCksumUnknown OlFlags = C.RTE_MBUF_F_RX_IP_CKSUM_UNKNOWN
+	RxIPCksumBad     OlFlags = C.RTE_MBUF_F_RX_IP_CKSUM_BAD
+	RxIPCksumGood    OlFlags = C.RTE_MBUF_F_RX_IP_CKSUM_GOOD
+	RxIPCksumNone    OlFlags = C.RTE_MBUF_F_RX_IP_CKSUM_NONE
+
+	RxL4CksumMask    OlFlags = C.RTE_MBUF_F_RX_L4_CKSUM_MASK
+	RxL4CksumUnknown OlFlags = C.RTE_MBUF_F_RX_L4_CKSUM_UNKNOWN
+	RxL4CksumBad     OlFlags = C.RTE_MBUF_F_RX_L4_CKSUM_BAD
+	RxL4CksumGood    OlFlags = C.RTE_MBUF_F_RX_L4_CKSUM_GOOD
+	RxL4CksumNone    OlFlags = C.RTE_MBUF_F_RX_L4_CKSUM_NONE
+)
+
+// TX offload flags.
+const (
+	TxOuterUDPCksum OlFlags = C.RTE_MBUF_F_TX_OUTER_UDP_CKSUM
+	TxUDPSeg        OlFlags = C.RTE_MBUF_F_TX_UDP_SEG
+	TxSecOffload    OlFlags = C.RTE_MBUF_F_TX_SEC_OFFLOAD
+	TxMACsec        OlFlags = C.RTE_MBUF_F_TX_MACSEC
+	TxQinQ          OlFlags = C.RTE_MBUF_F_TX_QINQ
+	TxTCPSeg        OlFlags = C.RTE_MBUF_F_TX_TCP_SEG
+	TxIEEE1588TMST  OlFlags = C.RTE_MBUF_F_TX_IEEE1588_TMST
+
+	TxL4Mask    OlFlags = C.RTE_MBUF_F_TX_L4_MASK
+	TxL4NoCksum OlFlags = C.RTE_MBUF_F_TX_L4_NO_CKSUM
+	TxTCPCksum  OlFlags = C.RTE_MBUF_F_TX_TCP_CKSUM
+	TxSCTPCksum OlFlags = C.RTE_MBUF_F_TX_SCTP_CKSUM
+	TxUDPCksum  OlFlags = C.RTE_MBUF_F_TX_UDP_CKSUM
+
+	TxIPCksum      OlFlags = C.RTE_MBUF_F_TX_IP_CKSUM
+	TxIPv4         OlFlags = C.RTE_MBUF_F_TX_IPV4
+	TxIPv6         OlFlags = C.RTE_MBUF_F_TX_IPV6
+	TxVLAN         OlFlags = C.RTE_MBUF_F_TX_VLAN
+	TxOuterIPCksum OlFlags = C.RTE_MBUF_F_TX_OUTER_IP_CKSUM
+	TxOuterIPv4    OlFlags = C.RTE_MBUF_F_TX_OUTER_IPV4
+	TxOuterIPv6    OlFlags = C.RTE_MBUF_F_TX_OUTER_IPV6
+
+	TxTunnelMask   OlFlags = C.RTE_MBUF_F_TX_TUNNEL_MASK
+	TxTunnelVXLAN  OlFlags = C.RTE_MBUF_F_TX_TUNNEL_VXLAN
+	TxTunnelGRE    OlFlags = C.RTE_MBUF_F_TX_TUNNEL_GRE
+	TxTunnelIPIP   OlFlags = C.RTE_MBUF_F_TX_TUNNEL_IPIP
+	TxTunnelGENEVE OlFlags = C.RTE_MBUF_F_TX_TUNNEL_GENEVE
+)
+
+// CksumStatus is the status of checksum validation reported by PMD.
+type CksumStatus int
+
+// Checksum validation statuses.
+const (
+	// No information about the checksum.
+	CksumUnknown CksumStatus = iota
+	// The checksum in the packet is wrong.
+	CksumBad
+	// The checksum in the packet is valid.
+	CksumGood
+	// The checksum is not correct in the packet data, but the
+	// integrity of the header/data is verified.
+	CksumNone
+)
+
+func (f OlFlags) cksum(mask, bad, good, none OlFlags) CksumStatus {
+	switch f & mask {
+	case bad:
+		return CksumBad
+	case good:
+		return CksumGood
+	case none:
+		return CksumNone
+	}
+	return CksumUnknown
+}
+
+// IPCksum returns IP checksum validation status.
+func (f OlFlags) IPCksum() CksumStatus {
+	return f.cksum(RxIPCksumMask, RxIPCksumBad, RxIPCksumGood, RxIPCksumNone)
+}
+
+// L4Cksum returns L4 checksum validation status.
+func (f OlFlags) L4Cksum() CksumStatus {
+	return f.cksum(RxL4CksumMask, RxL4CksumBad, RxL4CksumGood, RxL4CksumNone)
+}
+
+// OlFlags returns offload features flags of an mbuf.
+func (m *Mbuf) OlFlags() OlFlags {
+	return OlFlags(mbuf(m).ol_flags)
+}
+
+// SetOlFlags sets offload features flags of an mbuf.
+func (m *Mbuf) SetOlFlags(f OlFlags) {
+	mbuf(m).ol_flags = C.uint64_t(f)
+}
+
+// PacketType returns packet type (RTE_PTYPE_*) of an mbuf.
+func (m *Mbuf) PacketType() uint32 {
+	return *(*uint32)(unsafe.Add(unsafe.Pointer(m), C.MBUF_PTYPE_OFF))
+}
+
+// SetPacketType sets packet type (RTE_PTYPE_*) of an mbuf.
+func (m *Mbuf) SetPacketType(ptype uint32) {
+	*(*uint32)(unsafe.Add(unsafe.Pointer(m), C.MBUF_PTYPE_OFF)) = ptype
+}
+
+// FdirID returns the flow director ID or the mark ID set by the
+// MARK flow action. Valid only if RxFDIRID flag is set.
+func (m *Mbuf) FdirID() uint32 {
+	return *(*uint32)(unsafe.Add(unsafe.Pointer(m), C.MBUF_FDIR_HI_OFF))
+}
+
+// Port returns input port.
+func (m *Mbuf) Port() uint16 {
+	return uint16(mbuf(m).port)
+}
+
+// SetPort sets input port.
+func (m *Mbuf) SetPort(port uint16) {
+	mbuf(m).port = C.uint16_t(port)
+}
+
+// NbSegs returns number of segments of the packet.
+func (m *Mbuf) NbSegs() uint16 {
+	return uint16(mbuf(m).nb_segs)
+}
+
+// VlanTCI returns VLAN TCI (CPU order), valid if RxVLAN is set.
+func (m *Mbuf) VlanTCI() uint16 {
+	return uint16(mbuf(m).vlan_tci)
+}
+
+// SetVlanTCI sets VLAN TCI (CPU order) to be inserted if TxVLAN is
+// set.
+func (m *Mbuf) SetVlanTCI(tci uint16) {
+	mbuf(m).vlan_tci = C.uint16_t(tci)
+}
+
+// VlanTCIOuter returns outer VLAN TCI (CPU order), valid if RxQinQ
+// is set.
+func (m *Mbuf) VlanTCIOuter() uint16 {
+	return uint16(mbuf(m).vlan_tci_outer)
+}
+
+// SetVlanTCIOuter sets outer VLAN TCI (CPU order) to be inserted if
+// TxQinQ is set.
+func (m *Mbuf) SetVlanTCIOuter(tci uint16) {
+	mbuf(m).vlan_tci_outer = C.uint16_t(tci)
+}
+
+// TxOffload specifies header lengths needed by PMD to perform TX
+// offloads, e.g. checksum calculation or TSO.
+type TxOffload struct {
+	// L2 (MAC) header length.
+	L2Len uint16
+	// L3 (IP) header length.
+	L3Len uint16
+	// L4 (TCP/UDP) header length.
+	L4Len uint16
+	// TCP TSO segment size.
+	TSOSegSz uint16
+	// Outer L3 (IP) header length for tunneled packets.
+	OuterL3Len uint16
+	// Outer L2 (MAC) header length for tunneled packets.
+	OuterL2Len uint16
+}
+
+var _ = []uintptr{
+	unsafe.Sizeof(TxOffload{}) - unsafe.Sizeof(C.struct_go_tx_offload{}),
+	unsafe.Sizeof(C.struct_go_tx_offload{}) - unsafe.Sizeof(TxOffload{}),
+}
+
+// TxOffload returns header lengths of an mbuf.
+func (m *Mbuf) TxOffload() (tx TxOffload) {
+	C.get_tx_offload(mbuf(m), (*C.struct_go_tx_offload)(unsafe.Pointer(&tx)))
+	return
+}
+
+// SetTxOffload sets header lengths of an mbuf. Values exceeding the
+// bit widths of the mbuf fields are truncated.
+func (m *Mbuf) SetTxOffload(tx TxOffload) {
+	C.set_tx_offload(mbuf(m), (*C.struct_go_tx_offload)(unsafe.Pointer(&tx)))
+}
+
+// Timestamp is the dynamic mbuf field and flag for the timestamp.
+// The timestamp units and time reference are unspecified.
+type Timestamp struct {
+	offset uintptr
+	flag   OlFlags
+}
+
+// RxTimestampRegister registers dynamic field and flag for RX
+// timestamp. PMD fills the field if RTE_ETH_RX_OFFLOAD_TIMESTAMP is
+// enabled.
+func RxTimestampRegister() (*Timestamp, error) {
+	var offset C.int
+	var flag C.uint64_t
+	if err := common.IntErr(int64(C.rte_mbuf_dyn_rx_timestamp_register(&offset, &flag))); err != nil {
+		return nil, err
+	}
+	return &Timestamp{uintptr(offset), OlFlags(flag)}, nil
+}
+
+// TxTimestampRegister registers dynamic field and flag for TX
+// timestamp. PMD schedules the packet sending at the given time if
+// RTE_ETH_TX_OFFLOAD_SEND_ON_TIMESTAMP is enabled.
+func TxTimestampRegister() (*Timestamp, error) {
+	var offset C.int
+	var flag C.uint64_t
+	if err := common.IntErr(int64(C.rte_mbuf_dyn_tx_timestamp_register(&offset, &flag))); err != nil {
+		return nil, err
+	}
+	return &Timestamp{uintptr(offset), OlFlags(flag)}, nil
+}
+
+// Flag returns the offload flag signifying the timestamp is valid.
+func (ts *Timestamp) Flag() OlFlags {
+	return ts.flag
+}
+
+// Get returns the timestamp of an mbuf. Returns false if the
+// timestamp flag is not set.
+func (ts *Timestamp) Get(m *Mbuf) (uint64, bool) {
+	if m.OlFlags()&ts.flag == 0 {
+		return 0, false
+	}
+	return *(*uint64)(unsafe.Add(unsafe.Pointer(m), ts.offset)), true
+}
+
+// Set sets the timestamp of an mbuf and its flag.
+func (ts *Timestamp) Set(m *Mbuf, v uint64) {
+	*(*uint64)(unsafe.Add(unsafe.Pointer(m), ts.offset)) = v
+	m.SetOlFlags(m.OlFlags() | ts.flag)
+}
@@ -0,0 +1,89 @@
+package mbuf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yerden/go-dpdk/eal"
+	"github.com/yerden/go-dpdk/mempool"
+	"github.com/yerden/go-dpdk/packet"
+)
+
+func TestOlFlags(t *testing.T) {
+	assert.Equal(t, CksumGood, (RxIPCksumGood | RxL4CksumBad).IPCksum())
+	assert.Equal(t, CksumBad, (RxIPCksumGood | RxL4CksumBad).L4Cksum())
+	assert.Equal(t, CksumNone, RxIPCksumNone.IPCksum())
+	assert.Equal(t, CksumUnknown, OlFlags(0).L4Cksum())
+}
+
+func TestOffload(t *testing.T) {
+	eal.InitOnceSafe("test-mbuf", 1)
+
+	mp, err := mempool.CreateMbufPool("test-pool-offload", 100, 1500)
+	assert.NoError(t, err)
+	defer mp.Free()
+
+	m := PktMbufAlloc(mp)
+	assert.NotNil(t, m)
+	defer m.PktMbufFree()
+
+	assert.Equal(t, uint16(1), m.NbSegs())
+
+	m.SetOlFlags(TxIPv4 | TxIPCksum | TxTCPCksum)
+	assert.Equal(t, TxIPv4|TxIPCksum|TxTCPCksum, m.OlFlags())
+	assert.Equal(t, TxTCPCksum, m.OlFlags()&TxL4Mask)
+
+	tx := TxOffload{L2Len: 14, L3Len: 20, L4Len: 20, TSOSegSz: 1460}
+	m.SetTxOffload(tx)
+	assert.Equal(t, tx, m.TxOffload())
+
+	m.SetVlanTCI(100)
+	m.SetVlanTCIOuter(200)
+	assert.Equal(t, uint16(100), m.VlanTCI())
+	assert.Equal(t, uint16(200), m.VlanTCIOuter())
+
+	m.SetPacketType(0x11)
+	assert.Equal(t, uint32(0x11), m.PacketType())
+
+	m.SetPort(3)
+	assert.Equal(t, uint16(3), m.Port())
+
+	ts, err := RxTimestampRegister()
+	assert.NoError(t, err)
+	assert.NotZero(t, ts.Flag())
+
+	_, ok := ts.Get(m)
+	assert.False(t, ok)
+	ts.Set(m, 12345)
+	v, ok := ts.Get(m)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(12345), v)
+}
+
+func TestParseHeaders(t *testing.T) {
+	eal.InitOnceSafe("test-mbuf", 1)
+
+	mp, err := mempool.CreateMbufPool("test-pool-parse", 100, 1500)
+	assert.NoError(t, err)
+	defer mp.Free()
+
+	m := PktMbufAlloc(mp)
+	assert.NotNil(t, m)
+	defer m.PktMbufFree()
+
+	// Ethernet + IPv4 + UDP
+	pkt := make([]byte, 14+20+8+4)
+	pkt[12], pkt[13] = 0x08, 0x00
+	pkt[14] = 0x45
+	pkt[14+9] = 17
+	assert.NoError(t, m.PktMbufAppend(pkt))
+
+	// TSO segment size is preserved
+	m.SetTxOffload(TxOffload{TSOSegSz: 1000})
+
+	var p packet.Parser
+	var h packet.Headers
+	assert.NoError(t, m.ParseHeaders(&p, &h))
+	assert.Equal(t, TxOffload{L2Len: 14, L3Len: 20, L4Len: 8, TSOSegSz: 1000}, m.TxOffload())
+	assert.NotNil(t, h.UDP())
+}
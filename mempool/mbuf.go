@@ -2,20 +2,79 @@ package mempool
 
 /*
 #include <stdint.h>
+#include <stdlib.h>
 
 #include <rte_config.h>
 #include <rte_mbuf.h>
-
-static int is_priv_size_aligned(uint16_t priv_size) {
-	return RTE_ALIGN(priv_size, RTE_MBUF_PRIV_ALIGN) == priv_size;
-}
 */
 import "C"
 
 import (
+	"fmt"
+	"math"
+	"syscall"
 	"unsafe"
 )
 
+// MbufPrivAlign is the alignment of the mbuf private area size.
+const MbufPrivAlign = C.RTE_MBUF_PRIV_ALIGN
+
+// OptMbufPrivAlign rounds the size of private application data
+// specified by OptPrivateDataSize up to MbufPrivAlign. Without this
+// option the unaligned size is an error. This option is used in
+// CreateMbufPool and CreateMbufPoolExtBuf only.
+func OptMbufPrivAlign() Option {
+	return Option{func(conf *mpConf) {
+		conf.privAlign = true
+	}}
+}
+
+// OptMbufMinRxBufSize specifies the minimum size of Rx buffer the
+// mbufs are used for, e.g. ethdev.DevInfo.MinRxBufSize. The data room
+// size of the mbuf pool is validated to accommodate the headroom and
+// the buffer of this size. This option is used in CreateMbufPool and
+// CreateMbufPoolExtBuf only.
+func OptMbufMinRxBufSize(size uint32) Option {
+	return Option{func(conf *mpConf) {
+		conf.minRxBufSize = size
+	}}
+}
+
+// mbufConf applies options and validates mbuf pool parameters.
+func mbufConf(dataRoomSize uint16, opts []Option) (*mpConf, error) {
+	conf := &mpConf{socket: C.SOCKET_ID_ANY}
+	for i := range opts {
+		opts[i].f(conf)
+	}
+
+	// align on a wider type so that the size doesn't wrap around
+	privSize := uint64(conf.privDataSize)
+	if aligned := (privSize + MbufPrivAlign - 1) &^ (MbufPrivAlign - 1); aligned != privSize {
+		if !conf.privAlign {
+			return nil, fmt.Errorf("%w: mbuf private size %d is not aligned to %d",
+				syscall.EINVAL, privSize, MbufPrivAlign)
+		}
+		privSize = aligned
+	}
+
+	if privSize > math.MaxUint16 {
+		return nil, fmt.Errorf("%w: mbuf private size %d exceeds %d",
+			syscall.EINVAL, privSize, math.MaxUint16)
+	}
+	conf.privDataSize = C.uint(privSize)
+
+	if conf.minRxBufSize == 0 {
+		return conf, nil
+	}
+
+	if need := uint32(C.RTE_PKTMBUF_HEADROOM) + conf.minRxBufSize; uint32(dataRoomSize) < need {
+		return nil, fmt.Errorf("%w: mbuf data room size %d is less than headroom %d plus min Rx buffer size %d",
+			syscall.EINVAL, dataRoomSize, C.RTE_PKTMBUF_HEADROOM, conf.minRxBufSize)
+	}
+
+	return conf, nil
+}
+
 // CreateMbufPool creates mempool of mbufs. See CreateEmpty options
 // for a list of options. Only differences are described below.
 //
@@ -25,14 +84,17 @@ import (
 // OptPrivateDataSize semantics is different here. It specifies the
 // size of private application data between the rte_mbuf structure and
 // the data buffer.  This value must be aligned to
-// RTE_MBUF_PRIV_ALIGN.
+// RTE_MBUF_PRIV_ALIGN, see OptMbufPrivAlign.
+//
+// OptOpsName may be used to specify mempool ops, e.g. 'stack' or
+// 'lf_stack', in which case rte_pktmbuf_pool_create_by_ops is used.
 //
 // The created mempool is already populated and its objects are
 // initialized with rte_pktmbuf_init.
 func CreateMbufPool(name string, n uint32, dataRoomSize uint16, opts ...Option) (*Mempool, error) {
-	conf := &mpConf{socket: C.SOCKET_ID_ANY}
-	for i := range opts {
-		opts[i].f(conf)
+	conf, e := mbufConf(dataRoomSize, opts)
+	if e != nil {
+		return nil, e
 	}
 
 	cname := C.CString(name)
@@ -52,3 +114,76 @@ func CreateMbufPool(name string, n uint32, dataRoomSize uint16, opts ...Option)
 
 	return mp, nil
 }
+
+// ExtMem describes the external memory area which holds data
+// buffers of mbufs. The memory should be pinned, i.e. it should be
+// DPDK memory (e.g. memzone) or external memory registered in DPDK.
+type ExtMem struct {
+	// Start address of the area.
+	Addr unsafe.Pointer
+
+	// IO address of the area.
+	IOVA uint64
+
+	// Length of the area in bytes.
+	Len uintptr
+
+	// Size of each data buffer in the area. It should not be less
+	// than data room size of the pool.
+	EltSize uint16
+}
+
+// CreateMbufPoolExtBuf creates mempool of mbufs with data buffers
+// pinned in external memory areas ext. The mbufs are never detached
+// from their external buffers. The areas should provide at least n
+// buffers in total. This is useful for large buffers, e.g. for jumbo
+// frames, which can't be allocated within mbuf mempool objects.
+//
+// See CreateMbufPool for the description of other parameters.
+func CreateMbufPoolExtBuf(name string, n uint32, dataRoomSize uint16, ext []ExtMem, opts ...Option) (*Mempool, error) {
+	conf, e := mbufConf(dataRoomSize, opts)
+	if e != nil {
+		return nil, e
+	}
+
+	if len(ext) == 0 {
+		return nil, fmt.Errorf("%w: no external memory specified", syscall.EINVAL)
+	}
+
+	var total uint64
+	for i := range ext {
+		if ext[i].EltSize < dataRoomSize {
+			return nil, fmt.Errorf("%w: external buffer size %d is less than data room size %d",
+				syscall.EINVAL, ext[i].EltSize, dataRoomSize)
+		}
+		total += uint64(ext[i].Len) / uint64(ext[i].EltSize)
+	}
+
+	if total < uint64(n) {
+		return nil, fmt.Errorf("%w: external memory holds %d buffers, need %d",
+			syscall.EINVAL, total, n)
+	}
+
+	cext := (*C.struct_rte_pktmbuf_extmem)(C.calloc(C.size_t(len(ext)), C.sizeof_struct_rte_pktmbuf_extmem))
+	defer C.free(unsafe.Pointer(cext))
+	cexts := unsafe.Slice(cext, len(ext))
+	for i := range ext {
+		em := &cexts[i]
+		em.buf_ptr = ext[i].Addr
+		em.buf_iova = C.rte_iova_t(ext[i].IOVA)
+		em.buf_len = C.size_t(ext[i].Len)
+		em.elt_size = C.uint16_t(ext[i].EltSize)
+	}
+
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	mp := (*Mempool)(C.rte_pktmbuf_pool_create_extbuf(cname, C.uint(n),
+		conf.cacheSize, C.uint16_t(conf.privDataSize), C.uint16_t(dataRoomSize),
+		conf.socket, cext, C.uint(len(ext))))
+
+	if mp == nil {
+		return nil, err()
+	}
+
+	return mp, nil
+}
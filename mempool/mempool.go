@@ -16,6 +16,7 @@ import "C"
 
 import (
 	"reflect"
+	"syscall"
 	"unsafe"
 
 	"github.com/yerden/go-dpdk/common"
@@ -50,6 +51,10 @@ type mpConf struct {
 
 	// ops
 	opsName *string
+
+	// mbuf pool
+	privAlign    bool
+	minRxBufSize uint32
 }
 
 func err(n ...interface{}) error {
@@ -240,6 +245,53 @@ func goObjectCb(mp *C.struct_rte_mempool, opaque, obj unsafe.Pointer, objIdx C.u
 	fn(b)
 }
 
+//export goMemCb
+func goMemCb(mp *C.struct_rte_mempool, opaque unsafe.Pointer, hdr *C.struct_rte_mempool_memhdr, idx C.uint) {
+	cb := *(*common.ObjectID)(opaque)
+	fn := callbacks.Read(cb).(func(MemChunk))
+	fn(newMemChunk(hdr))
+}
+
+//export goOpsAlloc
+func goOpsAlloc(mp *C.struct_rte_mempool, name *C.char, id *C.uint64_t) C.int {
+	opsMu.Lock()
+	alloc := opsAllocs[C.GoString(name)]
+	opsMu.Unlock()
+
+	if alloc == nil {
+		return -C.int(syscall.ENOENT)
+	}
+
+	store, e := alloc((*Mempool)(mp))
+	if e != nil {
+		return opsErrno(e, syscall.ENOMEM)
+	}
+
+	*id = C.uint64_t(opsStores.Create(store))
+	return 0
+}
+
+//export goOpsFree
+func goOpsFree(id C.uint64_t) {
+	opsStore(id).Free()
+	opsStores.Delete(common.ObjectID(id))
+}
+
+//export goOpsEnqueue
+func goOpsEnqueue(id C.uint64_t, objs *unsafe.Pointer, n C.uint) C.int {
+	return opsErrno(opsStore(id).Enqueue(ptrSlice(objs, n)), syscall.ENOBUFS)
+}
+
+//export goOpsDequeue
+func goOpsDequeue(id C.uint64_t, objs *unsafe.Pointer, n C.uint) C.int {
+	return opsErrno(opsStore(id).Dequeue(ptrSlice(objs, n)), syscall.ENOBUFS)
+}
+
+//export goOpsGetCount
+func goOpsGetCount(id C.uint64_t) C.uint {
+	return C.uint(opsStore(id).Count())
+}
+
 // objIterC calls a function for each mempool element. Iterate across
 // all objects attached to a rte_mempool and call the callback
 // function on it.
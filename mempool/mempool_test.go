@@ -2,6 +2,8 @@ package mempool_test
 
 import (
 	"bytes"
+	"errors"
+	"math"
 	"reflect"
 	"syscall"
 	"testing"
@@ -12,6 +14,7 @@ import (
 	"github.com/yerden/go-dpdk/common"
 	"github.com/yerden/go-dpdk/eal"
 	"github.com/yerden/go-dpdk/mempool"
+	"github.com/yerden/go-dpdk/memzone"
 )
 
 func assert(t testing.TB, expected bool, args ...interface{}) {
@@ -145,6 +148,183 @@ func TestMempoolPriv(t *testing.T) {
 	})
 }
 
+func TestMempoolStats(t *testing.T) {
+	doOnMain(t, func(p *mempool.Mempool, data []byte) {
+		assert(t, p.Name() == "test_mbuf_pool", p.Name())
+		assert(t, p.Size() == 10240, p.Size())
+		assert(t, p.CacheSize() == 32, p.CacheSize())
+		assert(t, p.OpsName() == "stack", p.OpsName())
+
+		var out bytes.Buffer
+		assert(t, p.Dump(&out) == nil)
+		assert(t, bytes.Contains(out.Bytes(), []byte("mempool <test_mbuf_pool>")), out.String())
+		p.Audit()
+
+		m := mbuf.PktMbufAlloc(p)
+		assert(t, m != nil)
+		lcore := eal.LcoreID()
+		assert(t, p.CacheLen(lcore) > 0, p.CacheLen(lcore))
+		assert(t, p.CachedCount() == p.CacheLen(lcore), p.CachedCount())
+		assert(t, p.CommonPoolCount()+p.CachedCount() == p.AvailCount())
+		m.PktMbufFree()
+
+		var total uintptr
+		n := p.MemIter(func(c mempool.MemChunk) {
+			assert(t, c.Addr != nil)
+			total += c.Len
+		})
+		assert(t, n > 0, n)
+		assert(t, total >= uintptr(p.Size()*p.EltSize()), total)
+
+		_, err := p.OpsInfo()
+		assert(t, err == nil || err == syscall.ENOTSUP, err)
+
+		s, err := p.Stats()
+		if err == nil {
+			assert(t, s.GetSuccessObjs > 0, s)
+		} else {
+			assert(t, err == syscall.ENOTSUP, err)
+		}
+	})
+}
+
+type countingStore struct {
+	mempool.OpsStore
+	enq, deq int
+}
+
+func (s *countingStore) Enqueue(objs []unsafe.Pointer) error {
+	s.enq += len(objs)
+	return s.OpsStore.Enqueue(objs)
+}
+
+func (s *countingStore) Dequeue(objs []unsafe.Pointer) error {
+	err := s.OpsStore.Dequeue(objs)
+	if err == nil {
+		s.deq += len(objs)
+	}
+	return err
+}
+
+func TestRegisterOps(t *testing.T) {
+	eal.InitOnceSafe("test", 4)
+
+	var cs *countingStore
+	_, err := mempool.RegisterOps("go_test_ops", func(mp *mempool.Mempool) (mempool.OpsStore, error) {
+		s, err := mempool.NewStackStore(mp)
+		cs = &countingStore{OpsStore: s}
+		return cs, err
+	})
+	assert(t, err == nil, err)
+
+	_, err = mempool.RegisterOps("go_test_ops", mempool.NewStackStore)
+	assert(t, err == syscall.EEXIST, err)
+
+	err = eal.ExecOnMain(func(ctx *eal.LcoreCtx) {
+		mp, err := mempool.CreateMbufPool("test_mbuf_pool_go_ops", 1024, 2048,
+			mempool.OptOpsName("go_test_ops"),
+		)
+		assert(t, err == nil, err)
+		defer mp.Free()
+
+		assert(t, mp.OpsName() == "go_test_ops", mp.OpsName())
+		assert(t, mp.AvailCount() == 1024, mp.AvailCount())
+		assert(t, cs.enq == 1024, cs.enq)
+
+		ms := make([]*mbuf.Mbuf, 100)
+		assert(t, mbuf.PktMbufAllocBulk(mp, ms) == nil)
+		assert(t, mp.InUseCount() == 100, mp.InUseCount())
+		assert(t, cs.deq == 100, cs.deq)
+
+		// not enough objects
+		assert(t, mbuf.PktMbufAllocBulk(mp, make([]*mbuf.Mbuf, 1000)) != nil)
+
+		mbuf.PktMbufFreeBulk(ms)
+		assert(t, mp.InUseCount() == 0, mp.InUseCount())
+		assert(t, cs.enq == 1124, cs.enq)
+	})
+	assert(t, err == nil, err)
+}
+
+func TestMbufPoolOpts(t *testing.T) {
+	eal.InitOnceSafe("test", 4)
+
+	err := eal.ExecOnMain(func(ctx *eal.LcoreCtx) {
+		mp, err := mempool.CreateMbufPool("test_mbuf_pool_align", 128, 2048,
+			mempool.OptPrivateDataSize(63),
+			mempool.OptMbufPrivAlign(),
+		)
+		assert(t, err == nil, err)
+		assert(t, mbuf.PktMbufPrivSize(mp) == 64, mbuf.PktMbufPrivSize(mp))
+		mp.Free()
+
+		// the largest aligned size
+		const maxPrivSize = math.MaxUint16 &^ (mempool.MbufPrivAlign - 1)
+		mp, err = mempool.CreateMbufPool("test_mbuf_pool_align_max", 128, 2048,
+			mempool.OptPrivateDataSize(maxPrivSize-1),
+			mempool.OptMbufPrivAlign(),
+		)
+		assert(t, err == nil, err)
+		assert(t, mbuf.PktMbufPrivSize(mp) == maxPrivSize, mbuf.PktMbufPrivSize(mp))
+		mp.Free()
+
+		// aligned size doesn't fit into 16 bits
+		for _, size := range []uint32{maxPrivSize + 1, math.MaxUint16} {
+			_, err = mempool.CreateMbufPool("test_mbuf_pool_align_big", 128, 2048,
+				mempool.OptPrivateDataSize(size),
+				mempool.OptMbufPrivAlign(),
+			)
+			assert(t, errors.Is(err, syscall.EINVAL), size, err)
+		}
+
+		_, err = mempool.CreateMbufPool("test_mbuf_pool_small", 128, 2048,
+			mempool.OptMbufMinRxBufSize(2048),
+		)
+		assert(t, errors.Is(err, syscall.EINVAL), err)
+
+		_, err = mempool.CreateMbufPoolExtBuf("test_mbuf_pool_noext", 128, 2048, nil)
+		assert(t, errors.Is(err, syscall.EINVAL), err)
+	})
+	assert(t, err == nil, err)
+}
+
+func TestMbufPoolExtBuf(t *testing.T) {
+	eal.InitOnceSafe("test", 4)
+
+	err := eal.ExecOnMain(func(ctx *eal.LcoreCtx) {
+		mz, err := memzone.Reserve("test_mbuf_extmem", 256*4096)
+		assert(t, err == nil, err)
+		defer mz.Free()
+
+		ext := []mempool.ExtMem{{
+			Addr:    mz.Addr(),
+			IOVA:    mz.IOVA(),
+			Len:     mz.Len(),
+			EltSize: 4096,
+		}}
+
+		_, err = mempool.CreateMbufPoolExtBuf("test_mbuf_pool_ext", 512, 4096, ext)
+		assert(t, errors.Is(err, syscall.EINVAL), err)
+
+		mp, err := mempool.CreateMbufPoolExtBuf("test_mbuf_pool_ext", 256, 4096, ext)
+		assert(t, err == nil, err)
+		defer mp.Free()
+
+		m := mbuf.PktMbufAlloc(mp)
+		assert(t, m != nil)
+		defer m.PktMbufFree()
+
+		assert(t, m.HasExtBuf())
+		assert(t, m.BufLen() == 4096, m.BufLen())
+		assert(t, m.PktMbufAppend(make([]byte, 100)) == nil)
+
+		start := uintptr(mz.Addr())
+		addr := uintptr(unsafe.Pointer(&m.Data()[0]))
+		assert(t, addr >= start && addr < start+mz.Len(), addr)
+	})
+	assert(t, err == nil, err)
+}
+
 func TestResetAndAppendErr(t *testing.T) {
 	doOnMain(t, func(p *mempool.Mempool, data []byte) {
 		// test with slice of byte
@@ -0,0 +1,208 @@
+package mempool
+
+/*
+#include <errno.h>
+#include <stdlib.h>
+#include <string.h>
+
+#include <rte_config.h>
+#include <rte_mempool.h>
+
+extern int goOpsAlloc(struct rte_mempool *, char *, uint64_t *);
+extern void goOpsFree(uint64_t);
+extern int goOpsEnqueue(uint64_t, void **, unsigned);
+extern int goOpsDequeue(uint64_t, void **, unsigned);
+extern unsigned goOpsGetCount(uint64_t);
+
+static int go_ops_alloc(struct rte_mempool *mp)
+{
+	uint64_t id;
+	const struct rte_mempool_ops *ops = rte_mempool_get_ops(mp->ops_index);
+	int rc = goOpsAlloc(mp, (char *)ops->name, &id);
+	if (rc == 0)
+		mp->pool_id = id;
+	return rc;
+}
+
+static void go_ops_free(struct rte_mempool *mp)
+{
+	goOpsFree(mp->pool_id);
+}
+
+static int go_ops_enqueue(struct rte_mempool *mp, void * const *obj_table,
+		unsigned int n)
+{
+	return goOpsEnqueue(mp->pool_id, (void **)obj_table, n);
+}
+
+static int go_ops_dequeue(struct rte_mempool *mp, void **obj_table,
+		unsigned int n)
+{
+	return goOpsDequeue(mp->pool_id, obj_table, n);
+}
+
+static unsigned go_ops_get_count(const struct rte_mempool *mp)
+{
+	return goOpsGetCount(mp->pool_id);
+}
+
+static int go_register_ops(const char *name)
+{
+	struct rte_mempool_ops ops;
+
+	memset(&ops, 0, sizeof(ops));
+	if (strlen(name) >= sizeof(ops.name))
+		return -ENAMETOOLONG;
+
+	strcpy(ops.name, name);
+	ops.alloc = go_ops_alloc;
+	ops.free = go_ops_free;
+	ops.enqueue = go_ops_enqueue;
+	ops.dequeue = go_ops_dequeue;
+	ops.get_count = go_ops_get_count;
+	return rte_mempool_register_ops(&ops);
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"github.com/yerden/go-dpdk/common"
+)
+
+// OpsStore is the backing store of mempool objects implemented in
+// Go. Its methods are called by DPDK from the threads which allocate
+// and free the objects of the mempool, so it should be safe for
+// concurrent use unless the mempool is used by a single thread.
+//
+// Objects are the pointers to C memory so they may be stored in Go
+// memory.
+type OpsStore interface {
+	// Enqueue puts all objects into the store. The store should be
+	// able to hold all objects of the mempool.
+	Enqueue(objs []unsafe.Pointer) error
+
+	// Dequeue fills objs with objects from the store. It should
+	// either dequeue all of them or return an error, e.g.
+	// syscall.ENOBUFS, dequeuing nothing.
+	Dequeue(objs []unsafe.Pointer) error
+
+	// Count returns the number of objects in the store.
+	Count() int
+
+	// Free releases the store once the mempool is freed.
+	Free()
+}
+
+// OpsAllocFunc creates new OpsStore for the mempool. The size of the
+// mempool is known at the time of the call.
+type OpsAllocFunc func(mp *Mempool) (OpsStore, error)
+
+var (
+	opsMu     sync.Mutex
+	opsAllocs = map[string]OpsAllocFunc{}
+	opsStores = common.NewRegistryMap()
+)
+
+// RegisterOps registers new mempool ops under the name. The ops are
+// implemented by OpsStore created with alloc for each mempool with
+// these ops, see OptOpsName and SetOpsByName. Returns the index of
+// the ops.
+//
+// Every call to the OpsStore methods is a Cgo callback so these ops
+// are considerably slower than native DPDK ones. They are intended
+// for testing and instrumenting allocation patterns.
+//
+// In multi-process setup the ops should be registered in the same
+// order in all processes. Since OpsStore resides in the memory of the
+// process which created the mempool, such mempool can't be used in
+// other processes.
+func RegisterOps(name string, alloc OpsAllocFunc) (int, error) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+
+	opsMu.Lock()
+	defer opsMu.Unlock()
+
+	if _, ok := opsAllocs[name]; ok {
+		return 0, syscall.EEXIST
+	}
+
+	n, e := common.IntOrErr(C.go_register_ops(cname))
+	if e != nil {
+		return 0, e
+	}
+
+	opsAllocs[name] = alloc
+	return n, nil
+}
+
+func opsStore(id C.uint64_t) OpsStore {
+	return opsStores.Read(common.ObjectID(id)).(OpsStore)
+}
+
+func opsErrno(e error, def syscall.Errno) C.int {
+	if e == nil {
+		return 0
+	}
+
+	var errno syscall.Errno
+	if !errors.As(e, &errno) {
+		errno = def
+	}
+
+	return -C.int(errno)
+}
+
+func ptrSlice(objs *unsafe.Pointer, n C.uint) []unsafe.Pointer {
+	return unsafe.Slice(objs, int(n))
+}
+
+type stackStore struct {
+	sync.Mutex
+	objs []unsafe.Pointer
+}
+
+// NewStackStore creates OpsStore as a LIFO stack guarded by mutex.
+// It may be used with RegisterOps directly or wrapped to instrument
+// the mempool.
+func NewStackStore(mp *Mempool) (OpsStore, error) {
+	return &stackStore{objs: make([]unsafe.Pointer, 0, mp.Size())}, nil
+}
+
+func (s *stackStore) Enqueue(objs []unsafe.Pointer) error {
+	s.Lock()
+	s.objs = append(s.objs, objs...)
+	s.Unlock()
+	return nil
+}
+
+func (s *stackStore) Dequeue(objs []unsafe.Pointer) error {
+	s.Lock()
+	defer s.Unlock()
+
+	n := len(s.objs) - len(objs)
+	if n < 0 {
+		return syscall.ENOBUFS
+	}
+
+	copy(objs, s.objs[n:])
+	s.objs = s.objs[:n]
+	return nil
+}
+
+func (s *stackStore) Count() int {
+	s.Lock()
+	defer s.Unlock()
+	return len(s.objs)
+}
+
+func (s *stackStore) Free() {
+	s.Lock()
+	s.objs = nil
+	s.Unlock()
+}
@@ -0,0 +1,263 @@
+package mempool
+
+/*
+#include <errno.h>
+#include <stdio.h>
+#include <string.h>
+
+#include <rte_config.h>
+#include <rte_version.h>
+#include <rte_mempool.h>
+
+extern void goMemCb(struct rte_mempool *, void *, struct rte_mempool_memhdr *, unsigned);
+
+struct go_mempool_stats {
+	uint64_t put_bulk;
+	uint64_t put_objs;
+	uint64_t put_common_pool_bulk;
+	uint64_t put_common_pool_objs;
+	uint64_t get_common_pool_bulk;
+	uint64_t get_common_pool_objs;
+	uint64_t get_success_bulk;
+	uint64_t get_success_objs;
+	uint64_t get_fail_bulk;
+	uint64_t get_fail_objs;
+	uint64_t get_success_blks;
+	uint64_t get_fail_blks;
+};
+
+static int go_mempool_stats(const struct rte_mempool *mp, struct go_mempool_stats *s)
+{
+	memset(s, 0, sizeof(*s));
+#ifdef RTE_LIBRTE_MEMPOOL_STATS
+	unsigned i;
+	for (i = 0; i < RTE_DIM(mp->stats); i++) {
+		const struct rte_mempool_debug_stats *st = &mp->stats[i];
+		s->put_bulk += st->put_bulk;
+		s->put_objs += st->put_objs;
+		s->put_common_pool_bulk += st->put_common_pool_bulk;
+		s->put_common_pool_objs += st->put_common_pool_objs;
+		s->get_common_pool_bulk += st->get_common_pool_bulk;
+		s->get_common_pool_objs += st->get_common_pool_objs;
+		s->get_success_bulk += st->get_success_bulk;
+		s->get_success_objs += st->get_success_objs;
+		s->get_fail_bulk += st->get_fail_bulk;
+		s->get_fail_objs += st->get_fail_objs;
+		s->get_success_blks += st->get_success_blks;
+		s->get_fail_blks += st->get_fail_blks;
+	}
+#if RTE_VERSION >= RTE_VERSION_NUM(23, 7, 0, 0)
+	if (mp->cache_size != 0) {
+		for (i = 0; i < RTE_MAX_LCORE; i++) {
+			const struct rte_mempool_cache *c = &mp->local_cache[i];
+			s->put_bulk += c->stats.put_bulk;
+			s->put_objs += c->stats.put_objs;
+			s->get_success_bulk += c->stats.get_success_bulk;
+			s->get_success_objs += c->stats.get_success_objs;
+		}
+	}
+#endif
+	return 0;
+#else
+	(void)mp;
+	return -ENOTSUP;
+#endif
+}
+
+static int go_mempool_cache_len(struct rte_mempool *mp, unsigned lcore_id)
+{
+	struct rte_mempool_cache *c = rte_mempool_default_cache(mp, lcore_id);
+	return c == NULL ? 0 : (int)c->len;
+}
+
+static int go_mempool_cached_count(struct rte_mempool *mp)
+{
+	unsigned i;
+	int n = 0;
+
+	if (mp->cache_size == 0)
+		return 0;
+
+	for (i = 0; i < RTE_MAX_LCORE; i++)
+		n += mp->local_cache[i].len;
+
+	return n;
+}
+
+static const char *go_mempool_ops_name(struct rte_mempool *mp)
+{
+	return rte_mempool_get_ops(mp->ops_index)->name;
+}
+
+static rte_iova_t go_memhdr_iova(struct rte_mempool_memhdr *hdr)
+{
+	return hdr->iova;
+}
+*/
+import "C"
+
+import (
+	"io"
+	"unsafe"
+
+	"github.com/yerden/go-dpdk/common"
+)
+
+// Name returns the name of the mempool.
+func (mp *Mempool) Name() string {
+	return C.GoString(&mp.name[0])
+}
+
+// Size returns the maximum number of elements in the mempool.
+func (mp *Mempool) Size() uint32 {
+	return uint32(mp.size)
+}
+
+// EltSize returns the size of an element.
+func (mp *Mempool) EltSize() uint32 {
+	return uint32(mp.elt_size)
+}
+
+// CacheSize returns the size of per-lcore default cache.
+func (mp *Mempool) CacheSize() uint32 {
+	return uint32(mp.cache_size)
+}
+
+// OpsName returns the name of the mempool ops.
+func (mp *Mempool) OpsName() string {
+	return C.GoString(C.go_mempool_ops_name((*C.struct_rte_mempool)(mp)))
+}
+
+// Dump writes the status of the mempool into w.
+func (mp *Mempool) Dump(w io.Writer) error {
+	return common.DumpTo(w, func(fp unsafe.Pointer) {
+		C.rte_mempool_dump((*C.FILE)(fp), (*C.struct_rte_mempool)(mp))
+	})
+}
+
+// Audit checks the consistency of mempool objects. It aborts the
+// process with rte_panic if inconsistency is detected, which can't be
+// recovered from Go. The checks are performed only if DPDK is built
+// with RTE_LIBRTE_MEMPOOL_DEBUG, otherwise it does nothing.
+func (mp *Mempool) Audit() {
+	C.rte_mempool_audit((*C.struct_rte_mempool)(mp))
+}
+
+// CacheLen returns the number of objects in the default cache of the
+// specified lcore. Returns 0 if the cache is disabled.
+func (mp *Mempool) CacheLen(lcoreID uint) int {
+	return int(C.go_mempool_cache_len((*C.struct_rte_mempool)(mp), C.uint(lcoreID)))
+}
+
+// CachedCount returns the number of objects in the default caches of
+// all lcores. User-owned mempool caches are not accounted for.
+func (mp *Mempool) CachedCount() int {
+	return int(C.go_mempool_cached_count((*C.struct_rte_mempool)(mp)))
+}
+
+// CommonPoolCount returns the number of objects in the common pool,
+// i.e. not in per-lcore caches.
+func (mp *Mempool) CommonPoolCount() int {
+	return int(C.rte_mempool_ops_get_count((*C.struct_rte_mempool)(mp)))
+}
+
+// OpsInfo contains additional information about the mempool provided
+// by mempool ops.
+type OpsInfo struct {
+	// Number of objects in the contiguous block.
+	ContigBlockSize uint32
+}
+
+// OpsInfo returns additional information about the mempool. Returns
+// syscall.ENOTSUP if mempool ops doesn't provide it.
+func (mp *Mempool) OpsInfo() (OpsInfo, error) {
+	var info C.struct_rte_mempool_info
+	if e := err(C.rte_mempool_ops_get_info((*C.struct_rte_mempool)(mp), &info)); e != nil {
+		return OpsInfo{}, e
+	}
+
+	return OpsInfo{ContigBlockSize: uint32(info.contig_block_size)}, nil
+}
+
+// MemChunk is the memory chunk holding mempool objects.
+type MemChunk struct {
+	// Virtual address of the chunk.
+	Addr unsafe.Pointer
+
+	// IO address of the chunk.
+	IOVA uint64
+
+	// Length of the chunk.
+	Len uintptr
+}
+
+func newMemChunk(hdr *C.struct_rte_mempool_memhdr) MemChunk {
+	return MemChunk{
+		Addr: hdr.addr,
+		IOVA: uint64(C.go_memhdr_iova(hdr)),
+		Len:  uintptr(hdr.len),
+	}
+}
+
+// MemIter calls fn for each memory chunk of the mempool. Returns the
+// number of chunks iterated.
+func (mp *Mempool) MemIter(fn func(MemChunk)) uint32 {
+	cb := callbacks.Create(fn)
+	defer callbacks.Delete(cb)
+
+	return uint32(C.rte_mempool_mem_iter((*C.struct_rte_mempool)(mp),
+		(*C.rte_mempool_mem_cb_t)(C.goMemCb), unsafe.Pointer(&cb)))
+}
+
+// Stats are the mempool statistics summed across all lcores. They are
+// collected only if DPDK is built with RTE_LIBRTE_MEMPOOL_STATS.
+type Stats struct {
+	// Number of puts.
+	PutBulk uint64
+
+	// Number of objects successfully put.
+	PutObjs uint64
+
+	// Number of bulks enqueued in common pool.
+	PutCommonPoolBulk uint64
+
+	// Number of objects enqueued in common pool.
+	PutCommonPoolObjs uint64
+
+	// Number of bulks dequeued from common pool.
+	GetCommonPoolBulk uint64
+
+	// Number of objects dequeued from common pool.
+	GetCommonPoolObjs uint64
+
+	// Successful allocation number.
+	GetSuccessBulk uint64
+
+	// Objects successfully allocated.
+	GetSuccessObjs uint64
+
+	// Failed allocation number.
+	GetFailBulk uint64
+
+	// Objects that failed to be allocated.
+	GetFailObjs uint64
+
+	// Successful allocation number of contiguous blocks.
+	GetSuccessBlks uint64
+
+	// Failed allocation number of contiguous blocks.
+	GetFailBlks uint64
+}
+
+var _ = []uintptr{
+	unsafe.Sizeof(Stats{}) - unsafe.Sizeof(C.struct_go_mempool_stats{}),
+	unsafe.Sizeof(C.struct_go_mempool_stats{}) - unsafe.Sizeof(Stats{}),
+}
+
+// Stats returns the mempool statistics. Returns syscall.ENOTSUP if
+// DPDK is built without RTE_LIBRTE_MEMPOOL_STATS.
+func (mp *Mempool) Stats() (Stats, error) {
+	var s Stats
+	rc := C.go_mempool_stats((*C.struct_rte_mempool)(mp), (*C.struct_go_mempool_stats)(unsafe.Pointer(&s)))
+	return s, err(rc)
+}
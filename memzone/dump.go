@@ -0,0 +1,80 @@
+package memzone
+
+/*
+#include <stdio.h>
+
+#include <rte_config.h>
+#include <rte_memory.h>
+#include <rte_memzone.h>
+*/
+import "C"
+
+import (
+	"io"
+	"unsafe"
+
+	"github.com/yerden/go-dpdk/common"
+)
+
+// Dump writes the layout of all memzones into w.
+func Dump(w io.Writer) error {
+	return common.DumpTo(w, func(fp unsafe.Pointer) {
+		C.rte_memzone_dump((*C.FILE)(fp))
+	})
+}
+
+// DumpPhysmemLayout writes the layout of memory segments into w.
+func DumpPhysmemLayout(w io.Writer) error {
+	return common.DumpTo(w, func(fp unsafe.Pointer) {
+		C.rte_dump_physmem_layout((*C.FILE)(fp))
+	})
+}
+
+// Usage is the number and total length of memzones.
+type Usage struct {
+	// Number of memzones.
+	Count int
+
+	// Total length of memzones in bytes.
+	Len uint64
+}
+
+func (u *Usage) add(mz *Memzone) {
+	u.Count++
+	u.Len += uint64(mz.Len())
+}
+
+// Stats is the summary of reserved memzones.
+type Stats struct {
+	// Usage of all memzones.
+	Total Usage
+
+	// Usage per NUMA socket ID.
+	BySocket map[int]Usage
+
+	// Usage per page size.
+	ByPageSize map[uint64]Usage
+}
+
+// GetStats walks all memzones and returns the summary of reserved
+// memory.
+func GetStats() *Stats {
+	s := &Stats{
+		BySocket:   make(map[int]Usage),
+		ByPageSize: make(map[uint64]Usage),
+	}
+
+	Walk(func(mz *Memzone) {
+		s.Total.add(mz)
+
+		u := s.BySocket[mz.SocketID()]
+		u.add(mz)
+		s.BySocket[mz.SocketID()] = u
+
+		u = s.ByPageSize[mz.HugePageSz()]
+		u.add(mz)
+		s.ByPageSize[mz.HugePageSz()] = u
+	})
+
+	return s
+}
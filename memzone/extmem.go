@@ -0,0 +1,87 @@
+package memzone
+
+/*
+#include <rte_config.h>
+#include <rte_dev.h>
+#include <rte_memory.h>
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+func iovaArgs(iovas []uint64) (*C.rte_iova_t, C.uint) {
+	if len(iovas) == 0 {
+		return nil, 0
+	}
+	return (*C.rte_iova_t)(unsafe.Pointer(&iovas[0])), C.uint(len(iovas))
+}
+
+// ExtMemRegister registers the memory area of length bytes starting
+// at addr with DPDK, e.g. the memory mapped by the application. The
+// area consists of pages of pageSize bytes and iovas are the IO
+// addresses of the pages. iovas may be nil in which case IO addresses
+// are unknown.
+//
+// The registered memory is not added to any heap so it can't be used
+// for DPDK allocations, see malloc.HeapAddMemory for that. It is
+// mainly used for external buffers and DMA mapping with DMAMap.
+//
+// The memory should not be Go memory since it may be moved or freed by
+// Go runtime.
+func ExtMemRegister(addr unsafe.Pointer, length uintptr, iovas []uint64, pageSize uintptr) error {
+	iova, n := iovaArgs(iovas)
+	if C.rte_extmem_register(addr, C.size_t(length), iova, n, C.size_t(pageSize)) < 0 {
+		return err()
+	}
+	return nil
+}
+
+// ExtMemUnregister unregisters the memory area registered with
+// ExtMemRegister.
+func ExtMemUnregister(addr unsafe.Pointer, length uintptr) error {
+	if C.rte_extmem_unregister(addr, C.size_t(length)) < 0 {
+		return err()
+	}
+	return nil
+}
+
+// ExtMemAttach attaches the memory area registered by another process.
+// The memory should be mapped at the same address in this process.
+func ExtMemAttach(addr unsafe.Pointer, length uintptr) error {
+	if C.rte_extmem_attach(addr, C.size_t(length)) < 0 {
+		return err()
+	}
+	return nil
+}
+
+// ExtMemDetach detaches the memory area attached with ExtMemAttach.
+func ExtMemDetach(addr unsafe.Pointer, length uintptr) error {
+	if C.rte_extmem_detach(addr, C.size_t(length)) < 0 {
+		return err()
+	}
+	return nil
+}
+
+// DMAMap maps the memory area of length bytes starting at addr for
+// DMA by the device so that the device accesses it by iova. dev is
+// the pointer to struct rte_device, see ethdev.DevInfo.Device. The
+// memory should be registered with ExtMemRegister beforehand.
+//
+// Returns syscall.ENOTSUP if the bus of the device doesn't support
+// DMA mapping.
+func DMAMap(dev unsafe.Pointer, addr unsafe.Pointer, iova uint64, length uintptr) error {
+	if C.rte_dev_dma_map((*C.struct_rte_device)(dev), addr, C.uint64_t(iova), C.size_t(length)) < 0 {
+		return err()
+	}
+	return nil
+}
+
+// DMAUnmap unmaps the memory area mapped with DMAMap.
+func DMAUnmap(dev unsafe.Pointer, addr unsafe.Pointer, iova uint64, length uintptr) error {
+	if C.rte_dev_dma_unmap((*C.struct_rte_device)(dev), addr, C.uint64_t(iova), C.size_t(length)) < 0 {
+		return err()
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package memzone
+
+/*
+#include <stdint.h>
+
+#include <rte_config.h>
+#include <rte_memory.h>
+
+extern int goMemsegCb(struct rte_memseg_list *, struct rte_memseg *, uintptr_t);
+extern int goMemsegContigCb(struct rte_memseg_list *, struct rte_memseg *, size_t, uintptr_t);
+extern int goMemsegListCb(struct rte_memseg_list *, uintptr_t);
+
+static int go_memseg_cb(const struct rte_memseg_list *msl,
+		const struct rte_memseg *ms, void *arg)
+{
+	return goMemsegCb((struct rte_memseg_list *)msl,
+		(struct rte_memseg *)ms, (uintptr_t)arg);
+}
+
+static int go_memseg_contig_cb(const struct rte_memseg_list *msl,
+		const struct rte_memseg *ms, size_t len, void *arg)
+{
+	return goMemsegContigCb((struct rte_memseg_list *)msl,
+		(struct rte_memseg *)ms, len, (uintptr_t)arg);
+}
+
+static int go_memseg_list_cb(const struct rte_memseg_list *msl, void *arg)
+{
+	return goMemsegListCb((struct rte_memseg_list *)msl, (uintptr_t)arg);
+}
+
+static int go_memseg_walk(uintptr_t arg)
+{
+	return rte_memseg_walk(go_memseg_cb, (void *)arg);
+}
+
+static int go_memseg_contig_walk(uintptr_t arg)
+{
+	return rte_memseg_contig_walk(go_memseg_contig_cb, (void *)arg);
+}
+
+static int go_memseg_list_walk(uintptr_t arg)
+{
+	return rte_memseg_list_walk(go_memseg_list_cb, (void *)arg);
+}
+
+static void *go_memseg_addr(const struct rte_memseg *ms)
+{
+	return ms->addr;
+}
+
+static rte_iova_t go_memseg_iova(const struct rte_memseg *ms)
+{
+	return ms->iova;
+}
+
+static void *go_msl_base_va(const struct rte_memseg_list *msl)
+{
+	return msl->base_va;
+}
+*/
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/yerden/go-dpdk/common"
+)
+
+// BadIOVA is the invalid IO address.
+const BadIOVA = ^uint64(0)
+
+// Memseg is the physically contiguous memory segment, i.e. a page of
+// memory known to DPDK.
+type Memseg C.struct_rte_memseg
+
+// MemsegList is the list of memory segments of the same page size
+// and NUMA socket reserved as a single virtual area.
+type MemsegList C.struct_rte_memseg_list
+
+// Addr returns start virtual address of the segment.
+func (ms *Memseg) Addr() unsafe.Pointer {
+	return C.go_memseg_addr((*C.struct_rte_memseg)(ms))
+}
+
+// IOVA returns start IO address of the segment.
+func (ms *Memseg) IOVA() uint64 {
+	return uint64(C.go_memseg_iova((*C.struct_rte_memseg)(ms)))
+}
+
+// Len returns length of the segment.
+func (ms *Memseg) Len() uintptr {
+	return uintptr((*C.struct_rte_memseg)(ms).len)
+}
+
+// HugePageSz returns the page size of the segment.
+func (ms *Memseg) HugePageSz() uint64 {
+	return uint64((*C.struct_rte_memseg)(ms).hugepage_sz)
+}
+
+// SocketID returns NUMA socket ID of the segment.
+func (ms *Memseg) SocketID() int {
+	return int((*C.struct_rte_memseg)(ms).socket_id)
+}
+
+// BaseVA returns start virtual address of the list's area.
+func (msl *MemsegList) BaseVA() unsafe.Pointer {
+	return C.go_msl_base_va((*C.struct_rte_memseg_list)(msl))
+}
+
+// Len returns length of the list's area.
+func (msl *MemsegList) Len() uintptr {
+	return uintptr((*C.struct_rte_memseg_list)(msl).len)
+}
+
+// PageSz returns the page size of segments in the list.
+func (msl *MemsegList) PageSz() uint64 {
+	return uint64((*C.struct_rte_memseg_list)(msl).page_sz)
+}
+
+// SocketID returns NUMA socket ID of segments in the list.
+func (msl *MemsegList) SocketID() int {
+	return int((*C.struct_rte_memseg_list)(msl).socket_id)
+}
+
+// External tests if the list is the external memory, i.e. registered
+// with ExtMemRegister or added to external heap.
+func (msl *MemsegList) External() bool {
+	return (*C.struct_rte_memseg_list)(msl).external != 0
+}
+
+type memsegWalk struct {
+	fn  interface{}
+	err error
+}
+
+var memsegCallbacks = common.NewRegistryArray()
+
+func doMemsegWalk(fn interface{}, walk func(C.uintptr_t) C.int) error {
+	w := &memsegWalk{fn: fn}
+	id := memsegCallbacks.Create(w)
+	defer memsegCallbacks.Delete(id)
+
+	if walk(C.uintptr_t(id)) < 0 && w.err == nil {
+		return err()
+	}
+	return w.err
+}
+
+func (w *memsegWalk) ret(e error) C.int {
+	if e != nil {
+		w.err = e
+		return -1
+	}
+	return 0
+}
+
+// MemsegWalk calls fn for each allocated memory segment. If fn
+// returns an error, the walk stops and the error is returned.
+//
+// The memory hotplug lock is held during the walk so fn must not
+// allocate or free DPDK memory.
+func MemsegWalk(fn func(*MemsegList, *Memseg) error) error {
+	return doMemsegWalk(fn, func(arg C.uintptr_t) C.int {
+		return C.go_memseg_walk(arg)
+	})
+}
+
+// MemsegContigWalk calls fn for each chunk of physically contiguous
+// memory segments. length is the length of the chunk starting at ms.
+// See MemsegWalk.
+func MemsegContigWalk(fn func(msl *MemsegList, ms *Memseg, length uintptr) error) error {
+	return doMemsegWalk(fn, func(arg C.uintptr_t) C.int {
+		return C.go_memseg_contig_walk(arg)
+	})
+}
+
+// MemsegListWalk calls fn for each memory segment list. See
+// MemsegWalk.
+func MemsegListWalk(fn func(*MemsegList) error) error {
+	return doMemsegWalk(fn, func(arg C.uintptr_t) C.int {
+		return C.go_memseg_list_walk(arg)
+	})
+}
+
+// Virt2IOVA returns IO address of the virtual address in DPDK memory
+// or registered external memory. Returns BadIOVA if the address is
+// unknown.
+func Virt2IOVA(addr unsafe.Pointer) uint64 {
+	return uint64(C.rte_mem_virt2iova(addr))
+}
+
+// Virt2Memseg returns the memory segment of the virtual address. msl
+// may be nil in which case the list is looked up as well. Returns nil
+// if the address is unknown.
+func Virt2Memseg(addr unsafe.Pointer, msl *MemsegList) *Memseg {
+	return (*Memseg)(C.rte_mem_virt2memseg(addr, (*C.struct_rte_memseg_list)(msl)))
+}
+
+// Virt2MemsegList returns the memory segment list of the virtual
+// address. Returns nil if the address is unknown.
+func Virt2MemsegList(addr unsafe.Pointer) *MemsegList {
+	return (*MemsegList)(C.rte_mem_virt2memseg_list(addr))
+}
+
+// IOVA2Virt returns virtual address of the IO address in DPDK memory.
+// Returns nil if the address is unknown.
+func IOVA2Virt(iova uint64) unsafe.Pointer {
+	return C.rte_mem_iova2virt(C.rte_iova_t(iova))
+}
@@ -0,0 +1,38 @@
+package memzone
+
+/*
+#include <stdint.h>
+
+#include <rte_config.h>
+#include <rte_memory.h>
+*/
+import "C"
+
+import (
+	"github.com/yerden/go-dpdk/common"
+)
+
+func readMemsegWalk(arg C.uintptr_t) *memsegWalk {
+	return memsegCallbacks.Read(common.ObjectID(arg)).(*memsegWalk)
+}
+
+//export goMemsegCb
+func goMemsegCb(msl *C.struct_rte_memseg_list, ms *C.struct_rte_memseg, arg C.uintptr_t) C.int {
+	w := readMemsegWalk(arg)
+	fn := w.fn.(func(*MemsegList, *Memseg) error)
+	return w.ret(fn((*MemsegList)(msl), (*Memseg)(ms)))
+}
+
+//export goMemsegContigCb
+func goMemsegContigCb(msl *C.struct_rte_memseg_list, ms *C.struct_rte_memseg, length C.size_t, arg C.uintptr_t) C.int {
+	w := readMemsegWalk(arg)
+	fn := w.fn.(func(*MemsegList, *Memseg, uintptr) error)
+	return w.ret(fn((*MemsegList)(msl), (*Memseg)(ms), uintptr(length)))
+}
+
+//export goMemsegListCb
+func goMemsegListCb(msl *C.struct_rte_memseg_list, arg C.uintptr_t) C.int {
+	w := readMemsegWalk(arg)
+	fn := w.fn.(func(*MemsegList) error)
+	return w.ret(fn((*MemsegList)(msl)))
+}
@@ -19,7 +19,8 @@ package memzone
 #include <rte_memzone.h>
 
 enum {
-	OFF_MZ_ADDR = offsetof(struct rte_memzone, addr)
+	OFF_MZ_ADDR = offsetof(struct rte_memzone, addr),
+	OFF_MZ_IOVA = offsetof(struct rte_memzone, iova),
 };
 
 extern void mzCb(struct rte_memzone *, void *);
@@ -42,6 +43,9 @@ const (
 	// size is unavailable. If this flag is not set, the function will
 	// return error on an unavailable size request.
 	PageSizeHintOnly = C.RTE_MEMZONE_SIZE_HINT_ONLY
+
+	// The memzone must be IOVA-contiguous.
+	IOVAContig = C.RTE_MEMZONE_IOVA_CONTIG
 )
 
 type conf struct {
@@ -190,6 +194,14 @@ func (mz *Memzone) Addr() unsafe.Pointer {
 	return *(*unsafe.Pointer)(addr)
 }
 
+// IOVA returns start IO address of the memzone. The memzone is
+// contiguous in IO address space only if reserved with IOVAContig
+// flag.
+func (mz *Memzone) IOVA() uint64 {
+	addr := unsafe.Add(unsafe.Pointer(mz), C.OFF_MZ_IOVA)
+	return *(*uint64)(addr)
+}
+
 // Len returns length of the memzone.
 func (mz *Memzone) Len() uintptr {
 	return uintptr((*C.struct_rte_memzone)(mz).len)
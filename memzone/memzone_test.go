@@ -1,8 +1,12 @@
 package memzone_test
 
 import (
+	"bytes"
+	"errors"
+	"strings"
 	"syscall"
 	"testing"
+	"unsafe"
 
 	"github.com/yerden/go-dpdk/common"
 	"github.com/yerden/go-dpdk/eal"
@@ -122,3 +126,182 @@ func TestMemzoneAligned(t *testing.T) {
 	})
 	assert(err == nil, err)
 }
+
+func TestMemseg(t *testing.T) {
+	assert := common.Assert(t, true)
+
+	// Initialize EAL on all cores
+	eal.InitOnceSafe("test", 4)
+
+	err := eal.ExecOnMain(func(ctx *eal.LcoreCtx) {
+		mz, err := memzone.Reserve("test_mz_memseg", 4096,
+			memzone.OptSocket(eal.SocketID()))
+		assert(mz != nil && err == nil, err)
+		defer mz.Free()
+
+		addr := uintptr(mz.Addr())
+		ms := memzone.Virt2Memseg(mz.Addr(), nil)
+		assert(ms != nil)
+		assert(uintptr(ms.Addr()) <= addr && addr < uintptr(ms.Addr())+ms.Len())
+		assert(ms.SocketID() == mz.SocketID(), ms.SocketID())
+
+		msl := memzone.Virt2MemsegList(mz.Addr())
+		assert(msl != nil && !msl.External())
+		assert(msl.PageSz() == ms.HugePageSz(), msl.PageSz())
+		assert(memzone.Virt2Memseg(mz.Addr(), msl) == ms)
+
+		iova := memzone.Virt2IOVA(mz.Addr())
+		assert(iova == mz.IOVA() && iova != memzone.BadIOVA, iova)
+
+		var found bool
+		err = memzone.MemsegWalk(func(msl *memzone.MemsegList, ms1 *memzone.Memseg) error {
+			found = found || ms1 == ms
+			return nil
+		})
+		assert(err == nil && found, err)
+
+		// stop walking on error
+		var n int
+		err = memzone.MemsegListWalk(func(msl *memzone.MemsegList) error {
+			n++
+			return syscall.ECANCELED
+		})
+		assert(err == syscall.ECANCELED && n == 1, err, n)
+
+		var total uintptr
+		err = memzone.MemsegContigWalk(func(msl *memzone.MemsegList, ms *memzone.Memseg, length uintptr) error {
+			total += length
+			return nil
+		})
+		assert(err == nil && total >= ms.Len(), err, total)
+	})
+	assert(err == nil, err)
+}
+
+func TestExtMem(t *testing.T) {
+	assert := common.Assert(t, true)
+
+	// Initialize EAL on all cores
+	eal.InitOnceSafe("test", 4)
+
+	const pageSize = 4096
+	const length = 16 * pageSize
+
+	mem, err := syscall.Mmap(-1, 0, length, syscall.PROT_READ|syscall.PROT_WRITE,
+		syscall.MAP_ANONYMOUS|syscall.MAP_PRIVATE)
+	assert(err == nil, err)
+	defer syscall.Munmap(mem)
+
+	addr := unsafe.Pointer(&mem[0])
+	iovas := make([]uint64, length/pageSize)
+	for i := range iovas {
+		iovas[i] = 0x100000 + uint64(i*pageSize)
+	}
+
+	err = eal.ExecOnMain(func(ctx *eal.LcoreCtx) {
+		assert(memzone.ExtMemRegister(addr, length, iovas, pageSize) == nil)
+
+		msl := memzone.Virt2MemsegList(addr)
+		assert(msl != nil && msl.External())
+		assert(msl.BaseVA() == addr && msl.Len() == length, msl.Len())
+
+		ms := memzone.Virt2Memseg(unsafe.Pointer(&mem[pageSize+10]), msl)
+		assert(ms != nil && ms.IOVA() == iovas[1], ms)
+		assert(ms.Addr() == unsafe.Pointer(&mem[pageSize]) && ms.Len() == pageSize)
+
+		assert(memzone.ExtMemUnregister(addr, length) == nil)
+		assert(memzone.Virt2MemsegList(addr) == nil)
+	})
+	assert(err == nil, err)
+}
+
+type testShared struct {
+	Counter uint64
+	Flags   [4]uint32
+	Ready   bool
+}
+
+func TestMemzoneOf(t *testing.T) {
+	assert := common.Assert(t, true)
+
+	// Initialize EAL on all cores
+	eal.InitOnceSafe("test", 4)
+
+	err := eal.ExecOnMain(func(ctx *eal.LcoreCtx) {
+		shared, err := memzone.ReserveOf[testShared]("test_mz_typed", 2,
+			memzone.OptSocket(eal.SocketID()))
+		assert(shared != nil && err == nil, err)
+		defer shared.Free()
+
+		v := shared.Value()
+		assert(*v == testShared{})
+		v.Counter = 10
+		v.Flags[3] = 1
+
+		hdr := shared.Header()
+		assert(hdr.Magic == memzone.HeaderMagic && hdr.Version == 2 &&
+			hdr.Size == uint64(unsafe.Sizeof(testShared{})), hdr)
+
+		// as if looked up in secondary process
+		other, err := memzone.LookupOf[testShared]("test_mz_typed", 2)
+		assert(err == nil, err)
+		assert(other.Memzone() == shared.Memzone())
+		assert(other.Value().Counter == 10 && other.Value().Flags[3] == 1)
+
+		// version mismatch
+		_, err = memzone.LookupOf[testShared]("test_mz_typed", 3)
+		var le *memzone.LayoutError
+		assert(errors.As(err, &le) && le.Got.Version == 2 && le.Want.Version == 3, err)
+
+		// size mismatch
+		_, err = memzone.LookupOf[[3]uint64]("test_mz_typed", 2)
+		assert(errors.As(err, &le) && le.Got.Size != le.Want.Size, err)
+
+		// types with pointers are prohibited
+		_, err = memzone.ReserveOf[struct{ p *int }]("test_mz_typed_ptr", 1)
+		assert(errors.Is(err, syscall.EINVAL), err)
+		_, err = memzone.LookupOf[[2]string]("test_mz_typed", 2)
+		assert(errors.Is(err, syscall.EINVAL), err)
+	})
+	assert(err == nil, err)
+}
+
+func TestMemzoneDump(t *testing.T) {
+	assert := common.Assert(t, true)
+
+	// Initialize EAL on all cores
+	eal.InitOnceSafe("test", 4)
+
+	err := eal.ExecOnMain(func(ctx *eal.LcoreCtx) {
+		before := memzone.GetStats()
+
+		mz, err := memzone.Reserve("test_mz_dump", 4096,
+			memzone.OptSocket(eal.SocketID()))
+		assert(mz != nil && err == nil, err)
+		defer mz.Free()
+
+		after := memzone.GetStats()
+		assert(after.Total.Count == before.Total.Count+1, before, after)
+		assert(after.Total.Len == before.Total.Len+uint64(mz.Len()), before, after)
+
+		u := after.BySocket[mz.SocketID()]
+		assert(u.Count == before.BySocket[mz.SocketID()].Count+1, before, after)
+		u = after.ByPageSize[mz.HugePageSz()]
+		assert(u.Count == before.ByPageSize[mz.HugePageSz()].Count+1, before, after)
+
+		var total int
+		for _, u := range after.BySocket {
+			total += u.Count
+		}
+		assert(total == after.Total.Count, after)
+
+		var buf bytes.Buffer
+		assert(memzone.Dump(&buf) == nil)
+		assert(strings.Contains(buf.String(), "test_mz_dump"), buf.String())
+
+		buf.Reset()
+		assert(memzone.DumpPhysmemLayout(&buf) == nil)
+		assert(buf.Len() > 0)
+	})
+	assert(err == nil, err)
+}
@@ -0,0 +1,155 @@
+package memzone
+
+import (
+	"fmt"
+	"sync/atomic"
+	"unsafe"
+
+	"github.com/yerden/go-dpdk/common"
+)
+
+// HeaderMagic is the magic number of the header of typed memzone.
+const HeaderMagic uint32 = 0x5a4d4f47 // "GOMZ"
+
+// Header is the layout header placed at the start of typed memzone.
+// It allows primary and secondary processes to detect the mismatch
+// of the layout of shared structure, e.g. if the processes are built
+// from different sources.
+type Header struct {
+	// Magic number, HeaderMagic.
+	Magic uint32
+
+	// Version of the layout specified by the user.
+	Version uint32
+
+	// Size of the structure.
+	Size uint64
+}
+
+// headerSpace is the space reserved for Header so that the structure
+// is cache line aligned.
+const headerSpace = 64
+
+// LayoutError is returned if the header of typed memzone doesn't
+// match the expected one.
+type LayoutError struct {
+	// Name of the memzone.
+	Name string
+
+	// Expected and actual headers.
+	Want, Got Header
+}
+
+func (e *LayoutError) Error() string {
+	return fmt.Sprintf("memzone %q layout mismatch: want magic=%#x version=%d size=%d, got magic=%#x version=%d size=%d",
+		e.Name, e.Want.Magic, e.Want.Version, e.Want.Size,
+		e.Got.Magic, e.Got.Version, e.Got.Size)
+}
+
+// Of is the memzone holding the structure of type T shared between
+// processes. T should have fixed layout and contain no pointers, i.e.
+// consist of numbers, booleans, arrays and structs thereof.
+//
+// The structure is not protected in any way, the user should use
+// atomics or other synchronization primitives to access it from
+// several processes.
+type Of[T any] struct {
+	mz *Memzone
+}
+
+func typedHeader[T any](version uint32) (Header, error) {
+	if err := common.CheckNoPointers[T](); err != nil {
+		return Header{}, err
+	}
+
+	var v T
+
+	return Header{
+		Magic:   HeaderMagic,
+		Version: version,
+		Size:    uint64(unsafe.Sizeof(v)),
+	}, nil
+}
+
+// ReserveOf reserves the memzone for the structure of type T with
+// the layout of given version. The structure is zeroed. See Reserve
+// for options.
+func ReserveOf[T any](name string, version uint32, opts ...Option) (*Of[T], error) {
+	want, err := typedHeader[T](version)
+	if err != nil {
+		return nil, err
+	}
+
+	mz, err := Reserve(name, headerSpace+uintptr(want.Size), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Of[T]{mz}
+	hdr := t.header()
+	atomic.StoreUint32(&hdr.Magic, 0)
+
+	var zero T
+	*t.Value() = zero
+
+	// publish the header, magic number goes last
+	hdr.Version = want.Version
+	hdr.Size = want.Size
+	atomic.StoreUint32(&hdr.Magic, want.Magic)
+	return t, nil
+}
+
+// LookupOf searches the memzone with the structure of type T with
+// the layout of given version. Returns LayoutError if the memzone
+// header doesn't match.
+func LookupOf[T any](name string, version uint32) (*Of[T], error) {
+	want, err := typedHeader[T](version)
+	if err != nil {
+		return nil, err
+	}
+
+	mz, err := Lookup(name)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Of[T]{mz}
+	if mz.Len() < headerSpace {
+		return nil, &LayoutError{Name: name, Want: want}
+	}
+
+	if got := t.Header(); got != want {
+		return nil, &LayoutError{Name: name, Want: want, Got: got}
+	}
+
+	return t, nil
+}
+
+func (t *Of[T]) header() *Header {
+	return (*Header)(t.mz.Addr())
+}
+
+// Header returns the header of the memzone.
+func (t *Of[T]) Header() Header {
+	hdr := t.header()
+	return Header{
+		Magic:   atomic.LoadUint32(&hdr.Magic),
+		Version: hdr.Version,
+		Size:    hdr.Size,
+	}
+}
+
+// Value returns the pointer to the structure.
+func (t *Of[T]) Value() *T {
+	return (*T)(unsafe.Add(t.mz.Addr(), headerSpace))
+}
+
+// Memzone returns the underlying memzone.
+func (t *Of[T]) Memzone() *Memzone {
+	return t.mz
+}
+
+// Free frees the memzone. See Memzone.Free.
+func (t *Of[T]) Free() error {
+	return t.mz.Free()
+}
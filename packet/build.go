@@ -0,0 +1,326 @@
+package packet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"syscall"
+)
+
+// Header is the packet header which can be encoded into bytes. Packet
+// is built from the innermost header to the outermost one, so the
+// length of the data following the header is known when the header is
+// encoded.
+type Header interface {
+	// LayerType returns the type of the header.
+	LayerType() LayerType
+
+	// Len returns the length of the encoded header.
+	Len() int
+
+	// Put encodes the header into b which is at least Len bytes
+	// long. payloadLen is the length of the data following the header
+	// used to fill length fields. Checksums are zeroed.
+	Put(b []byte, payloadLen int)
+}
+
+var (
+	_ Header = (*EthernetHeader)(nil)
+	_ Header = (*VLANHeader)(nil)
+	_ Header = (*ARPHeader)(nil)
+	_ Header = (*IPv4Header)(nil)
+	_ Header = (*IPv6Header)(nil)
+	_ Header = (*UDPHeader)(nil)
+	_ Header = (*TCPHeader)(nil)
+	_ Header = (*ICMPHeader)(nil)
+	_ Header = (*ICMPv6Header)(nil)
+)
+
+// Build encodes headers hdrs followed by payload into b. Returns the
+// number of bytes written. b should be large enough to hold the whole
+// packet. Returns the error of ValidateHeaders if the headers can't be
+// encoded, b is not modified in this case.
+func Build(b []byte, payload []byte, hdrs ...Header) (int, error) {
+	if err := ValidateHeaders(hdrs...); err != nil {
+		return 0, err
+	}
+
+	n := len(payload)
+	for _, h := range hdrs {
+		n += h.Len()
+	}
+
+	off := n - len(payload)
+	copy(b[off:], payload)
+	for i := len(hdrs) - 1; i >= 0; i-- {
+		h := hdrs[i]
+		off -= h.Len()
+		h.Put(b[off:], n-off-h.Len())
+	}
+
+	return n, nil
+}
+
+// validator is implemented by headers which may be invalid, e.g. due
+// to the options length.
+type validator interface {
+	validate() error
+}
+
+// ValidateHeaders checks that headers hdrs can be encoded. Returns an
+// error wrapping syscall.EINVAL otherwise.
+func ValidateHeaders(hdrs ...Header) error {
+	for _, h := range hdrs {
+		if v, ok := h.(validator); ok {
+			if err := v.validate(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// MaxOptionsLen is the maximum length of IPv4 and TCP options limited
+// by the 4-bit header length field.
+const MaxOptionsLen = 40
+
+// optionsLen returns the padded length of options.
+func optionsLen(opts []byte) int {
+	return (len(opts) + 3) &^ 3
+}
+
+func validateOptions(layer LayerType, opts []byte) error {
+	if len(opts) > MaxOptionsLen {
+		return fmt.Errorf("%w: %v options length %d exceeds %d",
+			syscall.EINVAL, layer, len(opts), MaxOptionsLen)
+	}
+	return nil
+}
+
+// EthernetHeader is the Ethernet header.
+type EthernetHeader struct {
+	Dst, Src  net.HardwareAddr
+	EtherType uint16
+}
+
+// LayerType implements Header interface.
+func (h *EthernetHeader) LayerType() LayerType { return LayerEthernet }
+
+// Len implements Header interface.
+func (h *EthernetHeader) Len() int { return EthernetLen }
+
+// Put implements Header interface.
+func (h *EthernetHeader) Put(b []byte, _ int) {
+	copy(b[0:6], h.Dst)
+	copy(b[6:12], h.Src)
+	binary.BigEndian.PutUint16(b[12:], h.EtherType)
+}
+
+// VLANHeader is the 802.1Q or 802.1ad tag. It should follow the
+// Ethernet header with the corresponding EtherType.
+type VLANHeader struct {
+	// Tag Control Information.
+	TCI uint16
+
+	// EtherType of the payload.
+	EtherType uint16
+}
+
+// LayerType implements Header interface.
+func (h *VLANHeader) LayerType() LayerType { return LayerVLAN }
+
+// Len implements Header interface.
+func (h *VLANHeader) Len() int { return VLANLen }
+
+// Put implements Header interface.
+func (h *VLANHeader) Put(b []byte, _ int) {
+	binary.BigEndian.PutUint16(b[0:], h.TCI)
+	binary.BigEndian.PutUint16(b[2:], h.EtherType)
+}
+
+// ARPHeader is the ARP header for IPv4 over Ethernet.
+type ARPHeader struct {
+	// 1 for request, 2 for reply.
+	Operation uint16
+
+	SenderHW net.HardwareAddr
+	SenderIP net.IP
+	TargetHW net.HardwareAddr
+	TargetIP net.IP
+}
+
+// LayerType implements Header interface.
+func (h *ARPHeader) LayerType() LayerType { return LayerARP }
+
+// Len implements Header interface.
+func (h *ARPHeader) Len() int { return ARPLen }
+
+// Put implements Header interface.
+func (h *ARPHeader) Put(b []byte, _ int) {
+	binary.BigEndian.PutUint16(b[0:], 1) // Ethernet
+	binary.BigEndian.PutUint16(b[2:], EtherTypeIPv4)
+	b[4], b[5] = 6, 4
+	binary.BigEndian.PutUint16(b[6:], h.Operation)
+	copy(b[8:14], h.SenderHW)
+	copy(b[14:18], h.SenderIP.To4())
+	copy(b[18:24], h.TargetHW)
+	copy(b[24:28], h.TargetIP.To4())
+}
+
+// IPv4Header is the IPv4 header.
+type IPv4Header struct {
+	TOS          uint8
+	ID           uint16
+	DontFragment bool
+	TTL          uint8
+	Protocol     uint8
+	Src, Dst     net.IP
+
+	// Options are padded with zeros to 4 bytes boundary. Options
+	// should not exceed MaxOptionsLen bytes, see ValidateHeaders.
+	Options []byte
+}
+
+// LayerType implements Header interface.
+func (h *IPv4Header) LayerType() LayerType { return LayerIPv4 }
+
+// Len implements Header interface.
+func (h *IPv4Header) Len() int { return IPv4Len + optionsLen(h.Options) }
+
+func (h *IPv4Header) validate() error { return validateOptions(LayerIPv4, h.Options) }
+
+// Put implements Header interface.
+func (h *IPv4Header) Put(b []byte, payloadLen int) {
+	n := h.Len()
+	b[0] = 0x40 | uint8(n/4)
+	b[1] = h.TOS
+	binary.BigEndian.PutUint16(b[2:], uint16(n+payloadLen))
+	binary.BigEndian.PutUint16(b[4:], h.ID)
+	b[6], b[7] = 0, 0
+	if h.DontFragment {
+		b[6] = 0x40
+	}
+	b[8] = h.TTL
+	b[9] = h.Protocol
+	b[10], b[11] = 0, 0
+	copy(b[12:16], h.Src.To4())
+	copy(b[16:20], h.Dst.To4())
+	opts := b[IPv4Len:n]
+	for i := range opts {
+		opts[i] = 0
+	}
+	copy(opts, h.Options)
+}
+
+// IPv6Header is the IPv6 fixed header.
+type IPv6Header struct {
+	TrafficClass uint8
+	FlowLabel    uint32
+	NextHeader   uint8
+	HopLimit     uint8
+	Src, Dst     net.IP
+}
+
+// LayerType implements Header interface.
+func (h *IPv6Header) LayerType() LayerType { return LayerIPv6 }
+
+// Len implements Header interface.
+func (h *IPv6Header) Len() int { return IPv6Len }
+
+// Put implements Header interface.
+func (h *IPv6Header) Put(b []byte, payloadLen int) {
+	binary.BigEndian.PutUint32(b[0:], 6<<28|uint32(h.TrafficClass)<<20|h.FlowLabel&0xfffff)
+	binary.BigEndian.PutUint16(b[4:], uint16(payloadLen))
+	b[6] = h.NextHeader
+	b[7] = h.HopLimit
+	copy(b[8:24], h.Src.To16())
+	copy(b[24:40], h.Dst.To16())
+}
+
+// UDPHeader is the UDP header.
+type UDPHeader struct {
+	SrcPort, DstPort uint16
+}
+
+// LayerType implements Header interface.
+func (h *UDPHeader) LayerType() LayerType { return LayerUDP }
+
+// Len implements Header interface.
+func (h *UDPHeader) Len() int { return UDPLen }
+
+// Put implements Header interface.
+func (h *UDPHeader) Put(b []byte, payloadLen int) {
+	binary.BigEndian.PutUint16(b[0:], h.SrcPort)
+	binary.BigEndian.PutUint16(b[2:], h.DstPort)
+	binary.BigEndian.PutUint16(b[4:], uint16(UDPLen+payloadLen))
+	b[6], b[7] = 0, 0
+}
+
+// TCPHeader is the TCP header.
+type TCPHeader struct {
+	SrcPort, DstPort uint16
+	Seq, Ack         uint32
+	Flags            uint8
+	Window           uint16
+	Urgent           uint16
+
+	// Options are padded with zeros to 4 bytes boundary. Options
+	// should not exceed MaxOptionsLen bytes, see ValidateHeaders.
+	Options []byte
+}
+
+// LayerType implements Header interface.
+func (h *TCPHeader) LayerType() LayerType { return LayerTCP }
+
+// Len implements Header interface.
+func (h *TCPHeader) Len() int { return TCPLen + optionsLen(h.Options) }
+
+func (h *TCPHeader) validate() error { return validateOptions(LayerTCP, h.Options) }
+
+// Put implements Header interface.
+func (h *TCPHeader) Put(b []byte, _ int) {
+	n := h.Len()
+	binary.BigEndian.PutUint16(b[0:], h.SrcPort)
+	binary.BigEndian.PutUint16(b[2:], h.DstPort)
+	binary.BigEndian.PutUint32(b[4:], h.Seq)
+	binary.BigEndian.PutUint32(b[8:], h.Ack)
+	b[12] = uint8(n/4) << 4
+	b[13] = h.Flags
+	binary.BigEndian.PutUint16(b[14:], h.Window)
+	b[16], b[17] = 0, 0
+	binary.BigEndian.PutUint16(b[18:], h.Urgent)
+	opts := b[TCPLen:n]
+	for i := range opts {
+		opts[i] = 0
+	}
+	copy(opts, h.Options)
+}
+
+// ICMPHeader is the ICMPv4 header. ID and Seq are used in echo
+// messages, otherwise they represent the rest of the header.
+type ICMPHeader struct {
+	Type, Code uint8
+	ID, Seq    uint16
+}
+
+// LayerType implements Header interface.
+func (h *ICMPHeader) LayerType() LayerType { return LayerICMPv4 }
+
+// Len implements Header interface.
+func (h *ICMPHeader) Len() int { return ICMPLen }
+
+// Put implements Header interface.
+func (h *ICMPHeader) Put(b []byte, _ int) {
+	b[0], b[1] = h.Type, h.Code
+	b[2], b[3] = 0, 0
+	binary.BigEndian.PutUint16(b[4:], h.ID)
+	binary.BigEndian.PutUint16(b[6:], h.Seq)
+}
+
+// ICMPv6Header is the ICMPv6 header.
+type ICMPv6Header struct {
+	ICMPHeader
+}
+
+// LayerType implements Header interface.
+func (h *ICMPv6Header) LayerType() LayerType { return LayerICMPv6 }
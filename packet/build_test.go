@@ -0,0 +1,94 @@
+package packet
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+)
+
+func TestBuildUDPv4(t *testing.T) {
+	buf := make([]byte, 128)
+	n, err := Build(buf, []byte("payload"),
+		&EthernetHeader{Dst: testDstMAC, Src: testSrcMAC, EtherType: EtherTypeVLAN},
+		&VLANHeader{TCI: 100, EtherType: EtherTypeIPv4},
+		&IPv4Header{TTL: 64, Protocol: ProtoUDP, Src: testSrcIP4, Dst: testDstIP4,
+			DontFragment: true, Options: []byte{1}},
+		&UDPHeader{SrcPort: 1000, DstPort: 2000})
+	assert(t, err == nil, err)
+	assert(t, n == 14+4+24+8+7, n)
+
+	pkt := gopacket.NewPacket(buf[:n], layers.LayerTypeEthernet, gopacket.Default)
+	assert(t, pkt.ErrorLayer() == nil, pkt.ErrorLayer())
+
+	vlan := pkt.Layer(layers.LayerTypeDot1Q).(*layers.Dot1Q)
+	assert(t, vlan.VLANIdentifier == 100)
+
+	ip := pkt.Layer(layers.LayerTypeIPv4).(*layers.IPv4)
+	assert(t, ip.IHL == 6 && ip.Length == 24+8+7, ip.IHL, ip.Length)
+	assert(t, ip.Flags == layers.IPv4DontFragment)
+	assert(t, ip.SrcIP.Equal(testSrcIP4) && ip.DstIP.Equal(testDstIP4))
+
+	udp := pkt.Layer(layers.LayerTypeUDP).(*layers.UDP)
+	assert(t, udp.SrcPort == 1000 && udp.DstPort == 2000 && udp.Length == 15, udp)
+	assert(t, string(udp.Payload) == "payload")
+}
+
+func TestBuildTCPv6(t *testing.T) {
+	buf := make([]byte, 128)
+	n, err := Build(buf, nil,
+		&EthernetHeader{Dst: testDstMAC, Src: testSrcMAC, EtherType: EtherTypeIPv6},
+		&IPv6Header{NextHeader: ProtoTCP, HopLimit: 255, FlowLabel: 0x12345,
+			TrafficClass: 0x10, Src: testSrcIP6, Dst: testDstIP6},
+		&TCPHeader{SrcPort: 80, DstPort: 8080, Seq: 1, Flags: TCPFlagSYN,
+			Window: 100, Options: []byte{2, 4, 5, 0xb4}})
+	assert(t, err == nil, err)
+	assert(t, n == 14+40+24, n)
+
+	var p Parser
+	var h Headers
+	err = p.Parse(buf[:n], &h)
+	assert(t, err == nil, err)
+
+	ip := h.IPv6()
+	assert(t, ip.PayloadLen() == 24 && ip.FlowLabel() == 0x12345 && ip.TrafficClass() == 0x10)
+	assert(t, ip.Src().Equal(testSrcIP6))
+
+	tcp := h.TCP()
+	assert(t, tcp.HeaderLen() == 24 && tcp.Flags() == TCPFlagSYN && tcp.Window() == 100)
+}
+
+func TestBuildARP(t *testing.T) {
+	buf := make([]byte, 64)
+	n, err := Build(buf, nil,
+		&EthernetHeader{Dst: testDstMAC, Src: testSrcMAC, EtherType: EtherTypeARP},
+		&ARPHeader{Operation: 2, SenderHW: testSrcMAC, SenderIP: testSrcIP4,
+			TargetHW: testDstMAC, TargetIP: testDstIP4})
+	assert(t, err == nil, err)
+
+	pkt := gopacket.NewPacket(buf[:n], layers.LayerTypeEthernet, gopacket.Default)
+	arp := pkt.Layer(layers.LayerTypeARP).(*layers.ARP)
+	assert(t, arp.Operation == 2)
+	assert(t, string(arp.SourceProtAddress) == string(testSrcIP4.To4()))
+	assert(t, string(arp.DstHwAddress) == string(testDstMAC))
+}
+
+func TestBuildOptionsTooLong(t *testing.T) {
+	buf := make([]byte, 128)
+	for _, h := range []Header{
+		&IPv4Header{Options: make([]byte, MaxOptionsLen+1)},
+		&TCPHeader{Options: make([]byte, MaxOptionsLen+1)},
+	} {
+		err := ValidateHeaders(&EthernetHeader{}, h)
+		assert(t, errors.Is(err, syscall.EINVAL), h.LayerType(), err)
+
+		n, err := Build(buf, nil, h)
+		assert(t, n == 0 && errors.Is(err, syscall.EINVAL), h.LayerType(), err)
+	}
+
+	h := &TCPHeader{Options: make([]byte, MaxOptionsLen)}
+	assert(t, ValidateHeaders(h) == nil)
+	assert(t, h.Len() == TCPLen+MaxOptionsLen, h.Len())
+}
@@ -0,0 +1,376 @@
+package packet
+
+import (
+	"encoding/binary"
+	"net"
+)
+
+// LayerType is the type of decoded header.
+type LayerType uint8
+
+// Layer types.
+const (
+	LayerNone LayerType = iota
+	LayerEthernet
+	LayerVLAN
+	LayerARP
+	LayerIPv4
+	LayerIPv6
+	LayerIPv6Ext
+	LayerTCP
+	LayerUDP
+	LayerICMPv4
+	LayerICMPv6
+	LayerVXLAN
+	LayerGRE
+	LayerGTPU
+	LayerPayload
+)
+
+var layerNames = [...]string{
+	LayerNone:     "None",
+	LayerEthernet: "Ethernet",
+	LayerVLAN:     "VLAN",
+	LayerARP:      "ARP",
+	LayerIPv4:     "IPv4",
+	LayerIPv6:     "IPv6",
+	LayerIPv6Ext:  "IPv6Ext",
+	LayerTCP:      "TCP",
+	LayerUDP:      "UDP",
+	LayerICMPv4:   "ICMPv4",
+	LayerICMPv6:   "ICMPv6",
+	LayerVXLAN:    "VXLAN",
+	LayerGRE:      "GRE",
+	LayerGTPU:     "GTPU",
+	LayerPayload:  "Payload",
+}
+
+func (t LayerType) String() string {
+	if int(t) < len(layerNames) {
+		return layerNames[t]
+	}
+	return "Unknown"
+}
+
+// EtherType values.
+const (
+	EtherTypeIPv4 uint16 = 0x0800
+	EtherTypeARP  uint16 = 0x0806
+	EtherTypeVLAN uint16 = 0x8100
+	EtherTypeQinQ uint16 = 0x88A8
+	EtherTypeIPv6 uint16 = 0x86DD
+	EtherTypeTEB  uint16 = 0x6558 // Transparent Ethernet Bridging
+)
+
+// IP protocol numbers.
+const (
+	ProtoHopByHop uint8 = 0
+	ProtoICMPv4   uint8 = 1
+	ProtoIPIP     uint8 = 4
+	ProtoTCP      uint8 = 6
+	ProtoUDP      uint8 = 17
+	ProtoIPv6     uint8 = 41
+	ProtoRouting  uint8 = 43
+	ProtoFragment uint8 = 44
+	ProtoGRE      uint8 = 47
+	ProtoESP      uint8 = 50
+	ProtoAH       uint8 = 51
+	ProtoICMPv6   uint8 = 58
+	ProtoNoNext   uint8 = 59
+	ProtoDstOpts  uint8 = 60
+)
+
+// Minimal header lengths.
+const (
+	EthernetLen = 14
+	VLANLen     = 4
+	ARPLen      = 28
+	IPv4Len     = 20
+	IPv6Len     = 40
+	TCPLen      = 20
+	UDPLen      = 8
+	ICMPLen     = 8
+	VXLANLen    = 8
+	GRELen      = 4
+	GTPULen     = 8
+)
+
+// Ethernet is the view of Ethernet header.
+type Ethernet []byte
+
+// Dst returns destination MAC address.
+func (h Ethernet) Dst() net.HardwareAddr { return net.HardwareAddr(h[0:6]) }
+
+// Src returns source MAC address.
+func (h Ethernet) Src() net.HardwareAddr { return net.HardwareAddr(h[6:12]) }
+
+// EtherType returns EtherType of the payload.
+func (h Ethernet) EtherType() uint16 { return binary.BigEndian.Uint16(h[12:]) }
+
+// VLAN is the view of 802.1Q or 802.1ad tag following the Ethernet
+// source address, i.e. TCI and the EtherType of the payload.
+type VLAN []byte
+
+// TCI returns Tag Control Information.
+func (h VLAN) TCI() uint16 { return binary.BigEndian.Uint16(h[0:]) }
+
+// ID returns VLAN identifier.
+func (h VLAN) ID() uint16 { return h.TCI() & 0xfff }
+
+// Priority returns Priority Code Point.
+func (h VLAN) Priority() uint8 { return uint8(h.TCI() >> 13) }
+
+// DropEligible returns Drop Eligible Indicator.
+func (h VLAN) DropEligible() bool { return h.TCI()&0x1000 != 0 }
+
+// EtherType returns EtherType of the payload.
+func (h VLAN) EtherType() uint16 { return binary.BigEndian.Uint16(h[2:]) }
+
+// ARP is the view of ARP header for IPv4 over Ethernet.
+type ARP []byte
+
+// Operation returns ARP operation, 1 for request, 2 for reply.
+func (h ARP) Operation() uint16 { return binary.BigEndian.Uint16(h[6:]) }
+
+// SenderHW returns sender hardware address.
+func (h ARP) SenderHW() net.HardwareAddr { return net.HardwareAddr(h[8:14]) }
+
+// SenderIP returns sender protocol address.
+func (h ARP) SenderIP() net.IP { return net.IP(h[14:18]) }
+
+// TargetHW returns target hardware address.
+func (h ARP) TargetHW() net.HardwareAddr { return net.HardwareAddr(h[18:24]) }
+
+// TargetIP returns target protocol address.
+func (h ARP) TargetIP() net.IP { return net.IP(h[24:28]) }
+
+// IPv4 is the view of IPv4 header including options.
+type IPv4 []byte
+
+// Version returns IP version.
+func (h IPv4) Version() uint8 { return h[0] >> 4 }
+
+// HeaderLen returns header length in bytes.
+func (h IPv4) HeaderLen() int { return int(h[0]&0xf) * 4 }
+
+// TOS returns type of service.
+func (h IPv4) TOS() uint8 { return h[1] }
+
+// TotalLen returns total length of the packet.
+func (h IPv4) TotalLen() uint16 { return binary.BigEndian.Uint16(h[2:]) }
+
+// ID returns identification.
+func (h IPv4) ID() uint16 { return binary.BigEndian.Uint16(h[4:]) }
+
+// Flags returns fragmentation flags.
+func (h IPv4) Flags() uint8 { return h[6] >> 5 }
+
+// FragOffset returns fragment offset in 8-byte units.
+func (h IPv4) FragOffset() uint16 { return binary.BigEndian.Uint16(h[6:]) & 0x1fff }
+
+// MoreFragments returns true if MF flag is set.
+func (h IPv4) MoreFragments() bool { return h[6]&0x20 != 0 }
+
+// IsFragment returns true if the packet is a fragment.
+func (h IPv4) IsFragment() bool { return h.MoreFragments() || h.FragOffset() != 0 }
+
+// TTL returns time to live.
+func (h IPv4) TTL() uint8 { return h[8] }
+
+// Protocol returns the payload protocol.
+func (h IPv4) Protocol() uint8 { return h[9] }
+
+// Checksum returns header checksum.
+func (h IPv4) Checksum() uint16 { return binary.BigEndian.Uint16(h[10:]) }
+
+// Src returns source address.
+func (h IPv4) Src() net.IP { return net.IP(h[12:16]) }
+
+// Dst returns destination address.
+func (h IPv4) Dst() net.IP { return net.IP(h[16:20]) }
+
+// IPv6 is the view of IPv6 fixed header.
+type IPv6 []byte
+
+// Version returns IP version.
+func (h IPv6) Version() uint8 { return h[0] >> 4 }
+
+// TrafficClass returns traffic class.
+func (h IPv6) TrafficClass() uint8 { return uint8(binary.BigEndian.Uint16(h[0:]) >> 4) }
+
+// FlowLabel returns flow label.
+func (h IPv6) FlowLabel() uint32 { return binary.BigEndian.Uint32(h[0:]) & 0xfffff }
+
+// PayloadLen returns payload length including extension headers.
+func (h IPv6) PayloadLen() uint16 { return binary.BigEndian.Uint16(h[4:]) }
+
+// NextHeader returns next header type.
+func (h IPv6) NextHeader() uint8 { return h[6] }
+
+// HopLimit returns hop limit.
+func (h IPv6) HopLimit() uint8 { return h[7] }
+
+// Src returns source address.
+func (h IPv6) Src() net.IP { return net.IP(h[8:24]) }
+
+// Dst returns destination address.
+func (h IPv6) Dst() net.IP { return net.IP(h[24:40]) }
+
+// IPv6Ext is the view of IPv6 extension header.
+type IPv6Ext []byte
+
+// NextHeader returns next header type.
+func (h IPv6Ext) NextHeader() uint8 { return h[0] }
+
+// TCP is the view of TCP header including options.
+type TCP []byte
+
+// TCP flags.
+const (
+	TCPFlagFIN uint8 = 1 << iota
+	TCPFlagSYN
+	TCPFlagRST
+	TCPFlagPSH
+	TCPFlagACK
+	TCPFlagURG
+	TCPFlagECE
+	TCPFlagCWR
+)
+
+// SrcPort returns source port.
+func (h TCP) SrcPort() uint16 { return binary.BigEndian.Uint16(h[0:]) }
+
+// DstPort returns destination port.
+func (h TCP) DstPort() uint16 { return binary.BigEndian.Uint16(h[2:]) }
+
+// Seq returns sequence number.
+func (h TCP) Seq() uint32 { return binary.BigEndian.Uint32(h[4:]) }
+
+// Ack returns acknowledgment number.
+func (h TCP) Ack() uint32 { return binary.BigEndian.Uint32(h[8:]) }
+
+// HeaderLen returns header length in bytes.
+func (h TCP) HeaderLen() int { return int(h[12]>>4) * 4 }
+
+// Flags returns TCP flags.
+func (h TCP) Flags() uint8 { return h[13] }
+
+// Window returns window size.
+func (h TCP) Window() uint16 { return binary.BigEndian.Uint16(h[14:]) }
+
+// Checksum returns checksum.
+func (h TCP) Checksum() uint16 { return binary.BigEndian.Uint16(h[16:]) }
+
+// Urgent returns urgent pointer.
+func (h TCP) Urgent() uint16 { return binary.BigEndian.Uint16(h[18:]) }
+
+// UDP is the view of UDP header.
+type UDP []byte
+
+// SrcPort returns source port.
+func (h UDP) SrcPort() uint16 { return binary.BigEndian.Uint16(h[0:]) }
+
+// DstPort returns destination port.
+func (h UDP) DstPort() uint16 { return binary.BigEndian.Uint16(h[2:]) }
+
+// Length returns length of header and payload.
+func (h UDP) Length() uint16 { return binary.BigEndian.Uint16(h[4:]) }
+
+// Checksum returns checksum.
+func (h UDP) Checksum() uint16 { return binary.BigEndian.Uint16(h[6:]) }
+
+// ICMP is the view of ICMPv4 or ICMPv6 header.
+type ICMP []byte
+
+// Type returns message type.
+func (h ICMP) Type() uint8 { return h[0] }
+
+// Code returns message code.
+func (h ICMP) Code() uint8 { return h[1] }
+
+// Checksum returns checksum.
+func (h ICMP) Checksum() uint16 { return binary.BigEndian.Uint16(h[2:]) }
+
+// ID returns identifier of echo request or reply.
+func (h ICMP) ID() uint16 { return binary.BigEndian.Uint16(h[4:]) }
+
+// Seq returns sequence number of echo request or reply.
+func (h ICMP) Seq() uint16 { return binary.BigEndian.Uint16(h[6:]) }
+
+// VXLAN is the view of VXLAN header.
+type VXLAN []byte
+
+// Flags returns VXLAN flags.
+func (h VXLAN) Flags() uint8 { return h[0] }
+
+// VNI returns VXLAN network identifier.
+func (h VXLAN) VNI() uint32 { return binary.BigEndian.Uint32(h[4:]) >> 8 }
+
+// GRE is the view of GRE header including optional fields.
+type GRE []byte
+
+// GRE flags.
+const (
+	GREFlagChecksum uint16 = 0x8000
+	GREFlagKey      uint16 = 0x2000
+	GREFlagSeq      uint16 = 0x1000
+)
+
+// Flags returns flags and version.
+func (h GRE) Flags() uint16 { return binary.BigEndian.Uint16(h[0:]) }
+
+// Protocol returns EtherType of the payload.
+func (h GRE) Protocol() uint16 { return binary.BigEndian.Uint16(h[2:]) }
+
+// HeaderLen returns header length in bytes including optional
+// fields.
+func (h GRE) HeaderLen() int {
+	n, f := GRELen, h.Flags()
+	if f&GREFlagChecksum != 0 {
+		n += 4
+	}
+	if f&GREFlagKey != 0 {
+		n += 4
+	}
+	if f&GREFlagSeq != 0 {
+		n += 4
+	}
+	return n
+}
+
+// Key returns the key field. Returns false if the key is not present.
+func (h GRE) Key() (uint32, bool) {
+	f := h.Flags()
+	if f&GREFlagKey == 0 {
+		return 0, false
+	}
+	off := GRELen
+	if f&GREFlagChecksum != 0 {
+		off += 4
+	}
+	return binary.BigEndian.Uint32(h[off:]), true
+}
+
+// GTPU is the view of GTPv1-U header including optional fields and
+// extension headers.
+type GTPU []byte
+
+// GTPv1-U flags.
+const (
+	GTPUFlagExt uint8 = 0x04
+	GTPUFlagSeq uint8 = 0x02
+	GTPUFlagPN  uint8 = 0x01
+)
+
+// Flags returns version, protocol type and flags.
+func (h GTPU) Flags() uint8 { return h[0] }
+
+// MsgType returns message type, 255 for G-PDU.
+func (h GTPU) MsgType() uint8 { return h[1] }
+
+// Length returns length of the payload following the mandatory
+// header.
+func (h GTPU) Length() uint16 { return binary.BigEndian.Uint16(h[2:]) }
+
+// TEID returns tunnel endpoint identifier.
+func (h GTPU) TEID() uint32 { return binary.BigEndian.Uint32(h[4:]) }
@@ -0,0 +1,527 @@
+/*
+Package packet implements zero-copy decoding of packet headers.
+
+Parser decodes headers of a packet in a byte slice, e.g. obtained
+with mbuf.Mbuf.Data, into Headers which records the type, offset and
+length of every header found. Typed views such as IPv4 or TCP are
+slices of the original packet data so no allocation or copy is made.
+*/
+package packet
+
+import (
+	"errors"
+)
+
+// Decoding errors. Headers contain all the layers decoded before the
+// error occurred.
+var (
+	ErrTruncated     = errors.New("truncated header")
+	ErrMalformed     = errors.New("malformed header")
+	ErrTooManyLayers = errors.New("too many layers")
+)
+
+// MaxLayers is the maximum number of layers Headers can hold.
+const MaxLayers = 16
+
+// Default UDP destination ports of tunnel protocols.
+const (
+	DefaultVXLANPort = 4789
+	DefaultGTPUPort  = 2152
+)
+
+// Layer is the decoded header.
+type Layer struct {
+	// Type of the header.
+	Type LayerType
+
+	// Offset of the header from the beginning of the packet.
+	Offset int
+
+	// Length of the header including options and extensions.
+	Len int
+}
+
+// End returns offset of the byte following the header.
+func (l Layer) End() int {
+	return l.Offset + l.Len
+}
+
+// Headers is the result of packet decoding. Headers may be reused
+// across packets to avoid allocation.
+type Headers struct {
+	data   []byte
+	layers [MaxLayers]Layer
+	n      int
+}
+
+// Data returns the packet data Headers refer to.
+func (h *Headers) Data() []byte {
+	return h.data
+}
+
+// Layers returns decoded layers from the outermost to the innermost.
+func (h *Headers) Layers() []Layer {
+	return h.layers[:h.n]
+}
+
+// Bytes returns the header l.
+func (h *Headers) Bytes(l Layer) []byte {
+	return h.data[l.Offset:l.End()]
+}
+
+// Find returns the outermost layer of type t.
+func (h *Headers) Find(t LayerType) (Layer, bool) {
+	for _, l := range h.layers[:h.n] {
+		if l.Type == t {
+			return l, true
+		}
+	}
+	return Layer{}, false
+}
+
+// FindLast returns the innermost layer of type t.
+func (h *Headers) FindLast(t LayerType) (Layer, bool) {
+	for i := h.n - 1; i >= 0; i-- {
+		if l := h.layers[i]; l.Type == t {
+			return l, true
+		}
+	}
+	return Layer{}, false
+}
+
+func (h *Headers) find(t LayerType) []byte {
+	if l, ok := h.Find(t); ok {
+		return h.Bytes(l)
+	}
+	return nil
+}
+
+// Ethernet returns the outermost Ethernet header or nil.
+func (h *Headers) Ethernet() Ethernet { return h.find(LayerEthernet) }
+
+// VLAN returns the outermost VLAN tag or nil.
+func (h *Headers) VLAN() VLAN { return h.find(LayerVLAN) }
+
+// ARP returns ARP header or nil.
+func (h *Headers) ARP() ARP { return h.find(LayerARP) }
+
+// IPv4 returns the outermost IPv4 header or nil.
+func (h *Headers) IPv4() IPv4 { return h.find(LayerIPv4) }
+
+// IPv6 returns the outermost IPv6 header or nil.
+func (h *Headers) IPv6() IPv6 { return h.find(LayerIPv6) }
+
+// TCP returns the outermost TCP header or nil.
+func (h *Headers) TCP() TCP { return h.find(LayerTCP) }
+
+// UDP returns the outermost UDP header or nil.
+func (h *Headers) UDP() UDP { return h.find(LayerUDP) }
+
+// ICMPv4 returns the outermost ICMPv4 header or nil.
+func (h *Headers) ICMPv4() ICMP { return h.find(LayerICMPv4) }
+
+// ICMPv6 returns the outermost ICMPv6 header or nil.
+func (h *Headers) ICMPv6() ICMP { return h.find(LayerICMPv6) }
+
+// VXLAN returns VXLAN header or nil.
+func (h *Headers) VXLAN() VXLAN { return h.find(LayerVXLAN) }
+
+// GRE returns GRE header or nil.
+func (h *Headers) GRE() GRE { return h.find(LayerGRE) }
+
+// GTPU returns GTP-U header or nil.
+func (h *Headers) GTPU() GTPU { return h.find(LayerGTPU) }
+
+// Payload returns the data following the innermost decoded header.
+func (h *Headers) Payload() []byte {
+	if h.n == 0 {
+		return h.data
+	}
+
+	l := h.layers[h.n-1]
+	if l.Type == LayerPayload {
+		return h.Bytes(l)
+	}
+
+	return h.data[l.End():]
+}
+
+// Lens is the header lengths as expected by mbuf TX offloads.
+type Lens struct {
+	// Outer L2 and L3 header lengths, non-zero for tunneled packets
+	// only.
+	OuterL2, OuterL3 int
+
+	// L2 header length. For tunneled packets it's the length of outer
+	// L4, tunnel and inner L2 headers.
+	L2 int
+
+	// L3 header length including IPv6 extension headers.
+	L3 int
+
+	// TCP or UDP header length.
+	L4 int
+}
+
+func isL3(t LayerType) bool {
+	return t == LayerIPv4 || t == LayerIPv6
+}
+
+// l3 returns L3 length of the header at index i and the index of the
+// following layer.
+func (h *Headers) l3(i int) (int, int) {
+	j := i + 1
+	for j < h.n && h.layers[j].Type == LayerIPv6Ext {
+		j++
+	}
+	return h.layers[j-1].End() - h.layers[i].Offset, j
+}
+
+// Lens returns header lengths of decoded packet.
+func (h *Headers) Lens() (lens Lens) {
+	layers := h.layers[:h.n]
+
+	i := 0
+	for i < len(layers) && !isL3(layers[i].Type) {
+		if t := layers[i].Type; t == LayerEthernet || t == LayerVLAN {
+			lens.L2 = layers[i].End()
+		}
+		i++
+	}
+
+	if i == len(layers) {
+		return
+	}
+
+	lens.L2 = layers[i].Offset
+	l3, next := h.l3(i)
+
+	// look for inner L3 header
+	j := next
+	for j < len(layers) && !isL3(layers[j].Type) {
+		j++
+	}
+
+	if j < len(layers) {
+		lens.OuterL2, lens.OuterL3 = lens.L2, l3
+		lens.L2 = layers[j].Offset - layers[i].Offset - l3
+		l3, next = h.l3(j)
+	}
+
+	lens.L3 = l3
+	if next < len(layers) {
+		if l := layers[next]; l.Type == LayerTCP || l.Type == LayerUDP {
+			lens.L4 = l.Len
+		}
+	}
+
+	return
+}
+
+// Parser decodes packet headers. Zero value is ready to use and
+// decodes tunnels on default ports.
+type Parser struct {
+	// UDP destination port of VXLAN, DefaultVXLANPort if 0.
+	VXLANPort uint16
+
+	// UDP destination port of GTP-U, DefaultGTPUPort if 0.
+	GTPUPort uint16
+
+	// Do not decode tunnel payloads: VXLAN, GRE, GTP-U and IP in IP.
+	NoTunnels bool
+}
+
+// Parse decodes headers of the packet data starting from Ethernet
+// header. Decoding stops at unknown or non-first fragment payload
+// which is recorded as LayerPayload.
+func (p *Parser) Parse(data []byte, h *Headers) error {
+	return p.ParseFrom(LayerEthernet, data, h)
+}
+
+// ParseFrom decodes headers of the packet data starting from the
+// header of type first.
+func (p *Parser) ParseFrom(first LayerType, data []byte, h *Headers) error {
+	h.data, h.n = data, 0
+
+	var proto uint8
+	off, next := 0, first
+	for next != LayerNone && off < len(data) {
+		if h.n == MaxLayers {
+			return ErrTooManyLayers
+		}
+
+		t := next
+		n, err := p.decode(&next, &proto, data[off:])
+		if err != nil {
+			return err
+		}
+
+		h.layers[h.n] = Layer{t, off, n}
+		h.n++
+		off += n
+	}
+
+	return nil
+}
+
+func (p *Parser) etherNext(et uint16) LayerType {
+	switch et {
+	case EtherTypeIPv4:
+		return LayerIPv4
+	case EtherTypeIPv6:
+		return LayerIPv6
+	case EtherTypeVLAN, EtherTypeQinQ, 0x9100:
+		return LayerVLAN
+	case EtherTypeARP:
+		return LayerARP
+	case EtherTypeTEB:
+		return LayerEthernet
+	}
+	return LayerPayload
+}
+
+func (p *Parser) ipNext(proto uint8) LayerType {
+	switch proto {
+	case ProtoTCP:
+		return LayerTCP
+	case ProtoUDP:
+		return LayerUDP
+	case ProtoICMPv4:
+		return LayerICMPv4
+	case ProtoICMPv6:
+		return LayerICMPv6
+	case ProtoHopByHop, ProtoRouting, ProtoFragment, ProtoDstOpts, ProtoAH:
+		return LayerIPv6Ext
+	case ProtoNoNext:
+		return LayerNone
+	}
+
+	if !p.NoTunnels {
+		switch proto {
+		case ProtoGRE:
+			return LayerGRE
+		case ProtoIPIP:
+			return LayerIPv4
+		case ProtoIPv6:
+			return LayerIPv6
+		}
+	}
+
+	return LayerPayload
+}
+
+func ipVersionNext(b []byte) LayerType {
+	switch b[0] >> 4 {
+	case 4:
+		return LayerIPv4
+	case 6:
+		return LayerIPv6
+	}
+	return LayerPayload
+}
+
+func (p *Parser) udpNext(h UDP) LayerType {
+	if p.NoTunnels {
+		return LayerPayload
+	}
+
+	vxlan, gtpu := p.VXLANPort, p.GTPUPort
+	if vxlan == 0 {
+		vxlan = DefaultVXLANPort
+	}
+	if gtpu == 0 {
+		gtpu = DefaultGTPUPort
+	}
+
+	switch h.DstPort() {
+	case vxlan:
+		return LayerVXLAN
+	case gtpu:
+		return LayerGTPU
+	}
+	return LayerPayload
+}
+
+// decode decodes the header of type *next in b, returns its length
+// and sets the type of the following header into *next. proto keeps
+// the IP protocol number for IPv6 extension headers.
+func (p *Parser) decode(next *LayerType, proto *uint8, b []byte) (int, error) {
+	switch *next {
+	case LayerEthernet:
+		if len(b) < EthernetLen {
+			return 0, ErrTruncated
+		}
+		*next = p.etherNext(Ethernet(b).EtherType())
+		return EthernetLen, nil
+
+	case LayerVLAN:
+		if len(b) < VLANLen {
+			return 0, ErrTruncated
+		}
+		*next = p.etherNext(VLAN(b).EtherType())
+		return VLANLen, nil
+
+	case LayerARP:
+		if len(b) < ARPLen {
+			return 0, ErrTruncated
+		}
+		*next = LayerPayload
+		return ARPLen, nil
+
+	case LayerIPv4:
+		if len(b) < IPv4Len {
+			return 0, ErrTruncated
+		}
+		h := IPv4(b)
+		n := h.HeaderLen()
+		if h.Version() != 4 || n < IPv4Len {
+			return 0, ErrMalformed
+		}
+		if n > len(b) {
+			return 0, ErrTruncated
+		}
+		if h.FragOffset() != 0 {
+			*next = LayerPayload
+		} else {
+			*proto = h.Protocol()
+			*next = p.ipNext(*proto)
+		}
+		return n, nil
+
+	case LayerIPv6:
+		if len(b) < IPv6Len {
+			return 0, ErrTruncated
+		}
+		h := IPv6(b)
+		if h.Version() != 6 {
+			return 0, ErrMalformed
+		}
+		*proto = h.NextHeader()
+		*next = p.ipNext(*proto)
+		return IPv6Len, nil
+
+	case LayerIPv6Ext:
+		if len(b) < 8 {
+			return 0, ErrTruncated
+		}
+		n := 8
+		switch *proto {
+		case ProtoAH:
+			n = (int(b[1]) + 2) * 4
+		case ProtoFragment:
+			// non-first fragment
+			if b[2] != 0 || b[3]&0xf8 != 0 {
+				*next = LayerPayload
+				return n, nil
+			}
+		default:
+			n = (int(b[1]) + 1) * 8
+		}
+		if n > len(b) {
+			return 0, ErrTruncated
+		}
+		*proto = IPv6Ext(b).NextHeader()
+		*next = p.ipNext(*proto)
+		return n, nil
+
+	case LayerTCP:
+		if len(b) < TCPLen {
+			return 0, ErrTruncated
+		}
+		n := TCP(b).HeaderLen()
+		if n < TCPLen {
+			return 0, ErrMalformed
+		}
+		if n > len(b) {
+			return 0, ErrTruncated
+		}
+		*next = LayerPayload
+		return n, nil
+
+	case LayerUDP:
+		if len(b) < UDPLen {
+			return 0, ErrTruncated
+		}
+		*next = p.udpNext(UDP(b))
+		return UDPLen, nil
+
+	case LayerICMPv4, LayerICMPv6:
+		if len(b) < ICMPLen {
+			return 0, ErrTruncated
+		}
+		*next = LayerPayload
+		return ICMPLen, nil
+
+	case LayerVXLAN:
+		if len(b) < VXLANLen {
+			return 0, ErrTruncated
+		}
+		*next = LayerEthernet
+		return VXLANLen, nil
+
+	case LayerGRE:
+		if len(b) < GRELen {
+			return 0, ErrTruncated
+		}
+		h := GRE(b)
+		n := h.HeaderLen()
+		if n > len(b) {
+			return 0, ErrTruncated
+		}
+		// version 0 only
+		if h.Flags()&7 != 0 {
+			*next = LayerPayload
+		} else {
+			*next = p.etherNext(h.Protocol())
+		}
+		return n, nil
+
+	case LayerGTPU:
+		return p.decodeGTPU(next, b)
+	}
+
+	*next = LayerNone
+	return len(b), nil
+}
+
+func (p *Parser) decodeGTPU(next *LayerType, b []byte) (int, error) {
+	if len(b) < GTPULen {
+		return 0, ErrTruncated
+	}
+
+	h := GTPU(b)
+	// version 1, protocol type GTP
+	if h.Flags()&0xf0 != 0x30 {
+		return 0, ErrMalformed
+	}
+
+	n := GTPULen
+	if h.Flags()&(GTPUFlagExt|GTPUFlagSeq|GTPUFlagPN) != 0 {
+		n += 4
+		if n > len(b) {
+			return 0, ErrTruncated
+		}
+
+		if h.Flags()&GTPUFlagExt != 0 {
+			for ext := b[n-1]; ext != 0; ext = b[n-1] {
+				if n >= len(b) {
+					return 0, ErrTruncated
+				}
+				extLen := int(b[n]) * 4
+				if extLen == 0 {
+					return 0, ErrMalformed
+				}
+				if n += extLen; n > len(b) {
+					return 0, ErrTruncated
+				}
+			}
+		}
+	}
+
+	*next = LayerPayload
+	if h.MsgType() == 255 && n < len(b) {
+		*next = ipVersionNext(b[n:])
+	}
+
+	return n, nil
+}
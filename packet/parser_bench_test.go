@@ -0,0 +1,46 @@
+package packet
+
+import (
+	"testing"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+)
+
+func BenchmarkParse(b *testing.B) {
+	data := testTCPv4Packet(b)
+	var p Parser
+	var h Headers
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := p.Parse(data, &h); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodingLayerParser(b *testing.B) {
+	data := testTCPv4Packet(b)
+
+	var eth layers.Ethernet
+	var ip4 layers.IPv4
+	var ip6 layers.IPv6
+	var tcp layers.TCP
+	var udp layers.UDP
+	var payload gopacket.Payload
+	parser := gopacket.NewDecodingLayerParser(layers.LayerTypeEthernet,
+		&eth, &ip4, &ip6, &tcp, &udp, &payload)
+	decoded := make([]gopacket.LayerType, 0, 8)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := parser.DecodeLayers(data, &decoded); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package packet
+
+import (
+	"net"
+	"testing"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+)
+
+func assert(t testing.TB, expected bool, args ...interface{}) {
+	if !expected {
+		t.Helper()
+		t.Fatal(args...)
+	}
+}
+
+var (
+	testSrcMAC = net.HardwareAddr{0, 1, 2, 3, 4, 5}
+	testDstMAC = net.HardwareAddr{6, 7, 8, 9, 10, 11}
+	testSrcIP4 = net.IP{10, 0, 0, 1}
+	testDstIP4 = net.IP{10, 0, 0, 2}
+	testSrcIP6 = net.ParseIP("2001:db8::1")
+	testDstIP6 = net.ParseIP("2001:db8::2")
+)
+
+func serialize(t testing.TB, ls ...gopacket.SerializableLayer) []byte {
+	buf := gopacket.NewSerializeBuffer()
+	opts := gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+	if err := gopacket.SerializeLayers(buf, opts, ls...); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func eth(et layers.EthernetType) *layers.Ethernet {
+	return &layers.Ethernet{SrcMAC: testSrcMAC, DstMAC: testDstMAC, EthernetType: et}
+}
+
+func ip4(proto layers.IPProtocol) *layers.IPv4 {
+	return &layers.IPv4{Version: 4, IHL: 5, TTL: 64, Protocol: proto, SrcIP: testSrcIP4, DstIP: testDstIP4}
+}
+
+func ip6(nh layers.IPProtocol) *layers.IPv6 {
+	return &layers.IPv6{Version: 6, HopLimit: 64, NextHeader: nh, SrcIP: testSrcIP6, DstIP: testDstIP6}
+}
+
+func layerTypes(h *Headers) []LayerType {
+	var out []LayerType
+	for _, l := range h.Layers() {
+		out = append(out, l.Type)
+	}
+	return out
+}
+
+func equalTypes(a []LayerType, b ...LayerType) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func testTCPv4Packet(t testing.TB) []byte {
+	ip := ip4(layers.IPProtocolTCP)
+	ip.Options = []layers.IPv4Option{{OptionType: 1}, {OptionType: 1}, {OptionType: 1}, {OptionType: 0}}
+	tcp := &layers.TCP{SrcPort: 1234, DstPort: 80, Seq: 100, Ack: 200, SYN: true, ACK: true, Window: 1024}
+	tcp.Options = []layers.TCPOption{{OptionType: layers.TCPOptionKindMSS, OptionLength: 4, OptionData: []byte{5, 0xb4}}}
+	_ = tcp.SetNetworkLayerForChecksum(ip)
+	return serialize(t, eth(layers.EthernetTypeIPv4), ip, tcp, gopacket.Payload("hello"))
+}
+
+func TestParseTCPv4(t *testing.T) {
+	data := testTCPv4Packet(t)
+
+	var p Parser
+	var h Headers
+	err := p.Parse(data, &h)
+	assert(t, err == nil, err)
+	assert(t, equalTypes(layerTypes(&h), LayerEthernet, LayerIPv4, LayerTCP, LayerPayload), layerTypes(&h))
+
+	e := h.Ethernet()
+	assert(t, e.Src().String() == testSrcMAC.String())
+	assert(t, e.Dst().String() == testDstMAC.String())
+	assert(t, e.EtherType() == EtherTypeIPv4)
+
+	ip := h.IPv4()
+	assert(t, ip.HeaderLen() == 24, ip.HeaderLen())
+	assert(t, ip.Src().Equal(testSrcIP4) && ip.Dst().Equal(testDstIP4))
+	assert(t, ip.TTL() == 64 && ip.Protocol() == ProtoTCP)
+	assert(t, !ip.IsFragment())
+
+	tcp := h.TCP()
+	assert(t, tcp.SrcPort() == 1234 && tcp.DstPort() == 80)
+	assert(t, tcp.Seq() == 100 && tcp.Ack() == 200)
+	assert(t, tcp.Flags() == TCPFlagSYN|TCPFlagACK, tcp.Flags())
+	assert(t, tcp.HeaderLen() == 24, tcp.HeaderLen())
+	assert(t, string(h.Payload()) == "hello", h.Payload())
+
+	lens := h.Lens()
+	assert(t, lens == Lens{L2: 14, L3: 24, L4: 24}, lens)
+}
+
+func TestParseQinQIPv6Ext(t *testing.T) {
+	hbh := &layers.IPv6HopByHop{}
+	hbh.NextHeader = layers.IPProtocolUDP
+	hbh.Options = []*layers.IPv6HopByHopOption{{OptionType: 1, OptionLength: 4, OptionData: []byte{0, 0, 0, 0}}}
+
+	ip := ip6(layers.IPProtocolIPv6HopByHop)
+	udp := &layers.UDP{SrcPort: 1000, DstPort: 53}
+	_ = udp.SetNetworkLayerForChecksum(ip)
+
+	data := serialize(t, eth(layers.EthernetTypeQinQ),
+		&layers.Dot1Q{VLANIdentifier: 100, Priority: 3, Type: layers.EthernetTypeDot1Q},
+		&layers.Dot1Q{VLANIdentifier: 200, Type: layers.EthernetTypeIPv6},
+		ip, hbh, udp, gopacket.Payload("query"))
+
+	var p Parser
+	var h Headers
+	err := p.Parse(data, &h)
+	assert(t, err == nil, err)
+	assert(t, equalTypes(layerTypes(&h), LayerEthernet, LayerVLAN, LayerVLAN,
+		LayerIPv6, LayerIPv6Ext, LayerUDP, LayerPayload), layerTypes(&h))
+
+	vlan := h.VLAN()
+	assert(t, vlan.ID() == 100 && vlan.Priority() == 3, vlan.TCI())
+	l, _ := h.FindLast(LayerVLAN)
+	assert(t, VLAN(h.Bytes(l)).ID() == 200)
+
+	ip6h := h.IPv6()
+	assert(t, ip6h.Src().Equal(testSrcIP6) && ip6h.Dst().Equal(testDstIP6))
+	assert(t, ip6h.NextHeader() == ProtoHopByHop)
+	assert(t, h.UDP().DstPort() == 53)
+	assert(t, string(h.Payload()) == "query")
+
+	lens := h.Lens()
+	assert(t, lens == Lens{L2: 22, L3: 48, L4: 8}, lens)
+}
+
+func TestParseVXLAN(t *testing.T) {
+	outer := ip4(layers.IPProtocolUDP)
+	udp := &layers.UDP{SrcPort: 5000, DstPort: 4789}
+	_ = udp.SetNetworkLayerForChecksum(outer)
+	inner := ip4(layers.IPProtocolICMPv4)
+
+	data := serialize(t, eth(layers.EthernetTypeIPv4), outer, udp,
+		&layers.VXLAN{ValidIDFlag: true, VNI: 42},
+		eth(layers.EthernetTypeIPv4), inner,
+		&layers.ICMPv4{TypeCode: layers.CreateICMPv4TypeCode(8, 0), Id: 1, Seq: 2})
+
+	var p Parser
+	var h Headers
+	err := p.Parse(data, &h)
+	assert(t, err == nil, err)
+	assert(t, equalTypes(layerTypes(&h), LayerEthernet, LayerIPv4, LayerUDP,
+		LayerVXLAN, LayerEthernet, LayerIPv4, LayerICMPv4, LayerPayload), layerTypes(&h))
+	assert(t, h.VXLAN().VNI() == 42, h.VXLAN().VNI())
+	assert(t, h.ICMPv4().Type() == 8 && h.ICMPv4().Seq() == 2)
+
+	lens := h.Lens()
+	assert(t, lens == Lens{OuterL2: 14, OuterL3: 20, L2: 30, L3: 20}, lens)
+
+	// tunnels disabled
+	p.NoTunnels = true
+	err = p.Parse(data, &h)
+	assert(t, err == nil, err)
+	assert(t, equalTypes(layerTypes(&h), LayerEthernet, LayerIPv4, LayerUDP, LayerPayload), layerTypes(&h))
+}
+
+func TestParseGRE(t *testing.T) {
+	data := serialize(t, eth(layers.EthernetTypeIPv4), ip4(layers.IPProtocolGRE),
+		&layers.GRE{KeyPresent: true, Key: 7, Protocol: layers.EthernetTypeIPv6},
+		ip6(layers.IPProtocolNoNextHeader))
+
+	var p Parser
+	var h Headers
+	err := p.Parse(data, &h)
+	assert(t, err == nil, err)
+	assert(t, equalTypes(layerTypes(&h), LayerEthernet, LayerIPv4, LayerGRE, LayerIPv6), layerTypes(&h))
+
+	key, ok := h.GRE().Key()
+	assert(t, ok && key == 7, key)
+	assert(t, h.GRE().HeaderLen() == 8)
+
+	lens := h.Lens()
+	assert(t, lens == Lens{OuterL2: 14, OuterL3: 20, L2: 8, L3: 40}, lens)
+}
+
+func TestParseGTPU(t *testing.T) {
+	outer := ip4(layers.IPProtocolUDP)
+	udp := &layers.UDP{SrcPort: 2152, DstPort: 2152}
+	_ = udp.SetNetworkLayerForChecksum(outer)
+	inner := ip4(layers.IPProtocolUDP)
+	innerUDP := &layers.UDP{SrcPort: 1, DstPort: 2}
+	_ = innerUDP.SetNetworkLayerForChecksum(inner)
+
+	// G-PDU with PDU session container extension header
+	gtpu := gopacket.Payload{
+		0x34, 0xff, 0x00, 0x24, 0x00, 0x00, 0x12, 0x34,
+		0x00, 0x00, 0x00, 0x85, 0x01, 0x10, 0x05, 0x00,
+	}
+
+	data := serialize(t, eth(layers.EthernetTypeIPv4), outer, udp, gtpu, inner, innerUDP)
+
+	var p Parser
+	var h Headers
+	err := p.Parse(data, &h)
+	assert(t, err == nil, err)
+	assert(t, equalTypes(layerTypes(&h), LayerEthernet, LayerIPv4, LayerUDP,
+		LayerGTPU, LayerIPv4, LayerUDP), layerTypes(&h))
+	assert(t, h.GTPU().TEID() == 0x1234)
+
+	l, _ := h.Find(LayerGTPU)
+	assert(t, l.Len == 16, l)
+
+	lens := h.Lens()
+	assert(t, lens == Lens{OuterL2: 14, OuterL3: 20, L2: 24, L3: 20, L4: 8}, lens)
+}
+
+func TestParseARP(t *testing.T) {
+	data := serialize(t, eth(layers.EthernetTypeARP), &layers.ARP{
+		AddrType: layers.LinkTypeEthernet, Protocol: layers.EthernetTypeIPv4,
+		HwAddressSize: 6, ProtAddressSize: 4, Operation: 1,
+		SourceHwAddress: testSrcMAC, SourceProtAddress: testSrcIP4,
+		DstHwAddress: make([]byte, 6), DstProtAddress: testDstIP4,
+	})
+
+	var p Parser
+	var h Headers
+	err := p.Parse(data, &h)
+	assert(t, err == nil, err)
+	// Ethernet padding
+	assert(t, equalTypes(layerTypes(&h), LayerEthernet, LayerARP, LayerPayload), layerTypes(&h))
+
+	arp := h.ARP()
+	assert(t, arp.Operation() == 1)
+	assert(t, arp.SenderIP().Equal(testSrcIP4) && arp.TargetIP().Equal(testDstIP4))
+	assert(t, arp.SenderHW().String() == testSrcMAC.String())
+
+	lens := h.Lens()
+	assert(t, lens == Lens{L2: 14}, lens)
+}
+
+func TestParseErrors(t *testing.T) {
+	data := testTCPv4Packet(t)
+
+	var p Parser
+	var h Headers
+
+	err := p.Parse(data[:10], &h)
+	assert(t, err == ErrTruncated, err)
+	assert(t, len(h.Layers()) == 0)
+
+	// truncated TCP options
+	err = p.Parse(data[:14+24+22], &h)
+	assert(t, err == ErrTruncated, err)
+	assert(t, equalTypes(layerTypes(&h), LayerEthernet, LayerIPv4))
+
+	bad := append([]byte(nil), data...)
+	bad[14] = 0x41
+	err = p.Parse(bad, &h)
+	assert(t, err == ErrMalformed, err)
+
+	// IPv4 non-first fragment
+	frag := append([]byte(nil), data...)
+	frag[14+6] = 0x01
+	err = p.Parse(frag, &h)
+	assert(t, err == nil, err)
+	assert(t, equalTypes(layerTypes(&h), LayerEthernet, LayerIPv4, LayerPayload))
+	assert(t, h.IPv4().IsFragment())
+}
+
+func TestParseAllocs(t *testing.T) {
+	data := testTCPv4Packet(t)
+	var p Parser
+	var h Headers
+
+	n := testing.AllocsPerRun(100, func() {
+		_ = p.Parse(data, &h)
+		_ = h.TCP().DstPort()
+		_ = h.Lens()
+	})
+	assert(t, n == 0, n)
+}
@@ -0,0 +1,175 @@
+package ring
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// Backoff waits before the next attempt to poll the ring which was
+// found empty or full. attempt is the number of consecutive
+// unsuccessful polls starting from 1. Backoff should return early if
+// ctx is done.
+type Backoff func(ctx context.Context, attempt int)
+
+// BackoffSpin returns immediately so the ring is polled in a busy
+// loop. It provides the lowest latency at the cost of the whole CPU
+// core.
+func BackoffSpin(ctx context.Context, attempt int) {}
+
+// BackoffYield yields the processor allowing other goroutines to
+// run.
+func BackoffYield(ctx context.Context, attempt int) {
+	runtime.Gosched()
+}
+
+// BackoffSleep returns Backoff which sleeps for minDelay doubling it
+// with every attempt until it reaches maxDelay.
+func BackoffSleep(minDelay, maxDelay time.Duration) Backoff {
+	return func(ctx context.Context, attempt int) {
+		d := minDelay
+		for i := 1; i < attempt && d < maxDelay; i++ {
+			d *= 2
+		}
+		if d > maxDelay {
+			d = maxDelay
+		}
+
+		t := time.NewTimer(d)
+		defer t.Stop()
+
+		select {
+		case <-ctx.Done():
+		case <-t.C:
+		}
+	}
+}
+
+// Notifier is the eventfd based notification mechanism for the ring
+// consumers. Producers call Notify or write into FD after enqueueing
+// objects so that consumers waiting with Backoff wake up without
+// polling the ring continuously.
+type Notifier struct {
+	fd int
+}
+
+// NewNotifier creates new Notifier.
+func NewNotifier() (*Notifier, error) {
+	fd, err := unix.Eventfd(0, unix.EFD_NONBLOCK|unix.EFD_CLOEXEC)
+	if err != nil {
+		return nil, err
+	}
+	return &Notifier{fd}, nil
+}
+
+// FD returns the eventfd file descriptor. It may be used by producers
+// implemented in C or polled along with other file descriptors.
+func (n *Notifier) FD() int {
+	return n.fd
+}
+
+// Notify wakes up the consumer waiting on the Notifier.
+func (n *Notifier) Notify() error {
+	v := uint64(1)
+	b := (*[8]byte)(unsafe.Pointer(&v))[:]
+	if _, err := unix.Write(n.fd, b); err != nil && err != unix.EAGAIN {
+		return err
+	}
+	return nil
+}
+
+// Wait blocks until Notify is called or timeout expires. Negative
+// timeout means no timeout. Positive timeout is rounded up to
+// milliseconds. Returns true if the notification was received.
+func (n *Notifier) Wait(timeout time.Duration) (bool, error) {
+	ms := -1
+	if timeout >= 0 {
+		ms = int(timeout / time.Millisecond)
+		if timeout%time.Millisecond != 0 {
+			ms++
+		}
+	}
+
+	fds := []unix.PollFd{{Fd: int32(n.fd), Events: unix.POLLIN}}
+	k, err := unix.Poll(fds, ms)
+	if err == unix.EINTR {
+		return false, nil
+	} else if err != nil || k == 0 {
+		return false, err
+	}
+
+	var b [8]byte
+	if _, err := unix.Read(n.fd, b[:]); err != nil && err != unix.EAGAIN {
+		return false, err
+	}
+	return true, nil
+}
+
+// Close releases the Notifier.
+func (n *Notifier) Close() error {
+	return unix.Close(n.fd)
+}
+
+// Backoff returns Backoff which waits for the notification. timeout
+// limits the time of each wait so that the ring is polled even if a
+// producer doesn't notify. Negative timeout means no timeout. The wait
+// is interrupted once ctx is done.
+//
+// The returned Backoff starts a goroutine watching ctx on the first
+// wait and reuses it while ctx stays the same, so it should be
+// specified for a single adapter, e.g. a Reader or RecvChan.
+func (n *Notifier) Backoff(timeout time.Duration) Backoff {
+	w := &ctxWatcher{n: n}
+	return func(ctx context.Context, attempt int) {
+		if ctx.Err() != nil {
+			return
+		}
+
+		w.watch(ctx)
+		_, _ = n.Wait(timeout)
+	}
+}
+
+// ctxWatcher wakes up the Notifier once the watched context is done.
+type ctxWatcher struct {
+	n    *Notifier
+	mu   sync.Mutex
+	done <-chan struct{}
+	stop chan struct{}
+}
+
+// watch starts watching ctx unless it's already watched. The previous
+// watcher, if any, is stopped.
+func (w *ctxWatcher) watch(ctx context.Context) {
+	done := ctx.Done()
+	if done == nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.done == done {
+		return
+	}
+
+	if w.stop != nil {
+		close(w.stop)
+	}
+
+	stop := make(chan struct{})
+	w.done, w.stop = done, stop
+	go func() {
+		select {
+		case <-done:
+			// a spurious notification is harmless since the ring
+			// is polled anyway
+			_ = w.n.Notify()
+		case <-stop:
+		}
+	}()
+}
@@ -0,0 +1,118 @@
+package ring
+
+import (
+	"context"
+	"unsafe"
+)
+
+// RecvChan returns the channel into which the objects dequeued from
+// the ring are delivered. The objects are dequeued in bursts by a
+// separate goroutine which waits for the ring to become non-empty as
+// specified by OptBackoff. The goroutine stops and the channel is
+// closed when ctx is done. The objects dequeued but not delivered by
+// then are passed to the function specified by OptDrop, if any.
+//
+// The goroutine acts as a consumer of the ring so the ring should be
+// multi-consumer unless it is the only consumer.
+func (r *Ring) RecvChan(ctx context.Context, opts ...AdapterOption) <-chan unsafe.Pointer {
+	rd := NewReader(r, opts...)
+	ch := make(chan unsafe.Pointer, rd.conf.size)
+
+	go func() {
+		defer close(ch)
+		buf := make([]unsafe.Pointer, rd.conf.burst)
+		for {
+			n, err := rd.Read(ctx, buf)
+			if err != nil {
+				return
+			}
+
+			for i, obj := range buf[:n] {
+				select {
+				case ch <- obj:
+				case <-ctx.Done():
+					rd.conf.dropObjs(buf[i:n])
+					return
+				}
+			}
+		}
+	}()
+
+	return ch
+}
+
+// SendChan returns the channel from which the objects are enqueued
+// into the ring. The objects are enqueued in bursts by a separate
+// goroutine which waits for the ring to become non-full as specified
+// by OptBackoff. The goroutine stops when the channel is closed after
+// enqueuing the remaining objects or when ctx is done. The objects
+// received from the channel but not enqueued by then, as well as the
+// objects still buffered in the channel, are passed to the function
+// specified by OptDrop, if any. Objects should not be sent after ctx
+// is done since nobody would receive them.
+//
+// The goroutine acts as a producer of the ring so the ring should be
+// multi-producer unless it is the only producer.
+func (r *Ring) SendChan(ctx context.Context, opts ...AdapterOption) chan<- unsafe.Pointer {
+	w := NewWriter(r, opts...)
+	ch := make(chan unsafe.Pointer, w.conf.size)
+
+	go func() {
+		defer func() {
+			w.conf.dropObjs(w.buf)
+			w.conf.drainChan(ch)
+		}()
+		var one [1]unsafe.Pointer
+		for {
+			var ok bool
+			select {
+			case one[0], ok = <-ch:
+			case <-ctx.Done():
+				return
+			}
+
+			if !ok {
+				_ = w.Flush(ctx)
+				return
+			}
+
+			if n, err := w.Write(ctx, one[:]); err != nil {
+				w.conf.dropObjs(one[n:])
+				return
+			}
+
+			// flush if there are no more objects pending
+			if len(ch) == 0 {
+				if err := w.Flush(ctx); err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	return ch
+}
+
+func (conf *adapterConf) dropObjs(objs []unsafe.Pointer) {
+	if conf.drop != nil && len(objs) > 0 {
+		conf.drop(objs)
+	}
+}
+
+// drainChan drops the objects buffered in ch without blocking.
+func (conf *adapterConf) drainChan(ch <-chan unsafe.Pointer) {
+	var objs []unsafe.Pointer
+	defer func() { conf.dropObjs(objs) }()
+
+	for {
+		select {
+		case obj, ok := <-ch:
+			if !ok {
+				return
+			}
+			objs = append(objs, obj)
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,313 @@
+package ring
+
+/*
+#include <stdlib.h>
+
+#include <rte_config.h>
+#include <rte_ring.h>
+#include <rte_ring_elem.h>
+
+#include "ring.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+
+	"github.com/yerden/go-dpdk/common"
+)
+
+// CreateElem creates new ring named name in memory with elements of
+// size esize bytes. esize must be a multiple of 4.
+//
+// See Create for the description of other parameters. The ring is
+// added in RTE_TAILQ_RING list.
+func CreateElem(name string, esize, count uint, opts ...Option) (*Ring, error) {
+	rc := makeOpts(name, opts)
+	r := (*Ring)(C.rte_ring_create_elem(rc.cname, C.uint(esize), C.uint(count), rc.socket, rc.flags))
+	if r == nil {
+		return nil, err()
+	}
+	return r, nil
+}
+
+// GetMemSizeElem calculates the memory size needed for a ring with
+// count elements of size esize bytes.
+//
+// esize must be a multiple of 4 and count should be power of 2. If
+// that is not the case, EINVAL error will be returned.
+func GetMemSizeElem(esize, count uint) (int, error) {
+	sz := C.rte_ring_get_memsize_elem(C.uint(esize), C.uint(count))
+	return common.IntOrErr(int(sz))
+}
+
+// NewElem allocates and initializes Ring in Go memory with elements
+// of size esize bytes. See New.
+func NewElem(name string, esize, count uint, opts ...Option) (*Ring, error) {
+	size, err := GetMemSizeElem(esize, count)
+	if err != nil {
+		return nil, err
+	}
+
+	p := make([]byte, size)
+	r := (*Ring)(unsafe.Pointer(&p[0]))
+	return r, r.Init(name, count, opts...)
+}
+
+func elemArgs(r *Ring, obj unsafe.Pointer, esize, n int) (*C.struct_rte_ring,
+	C.uintptr_t, C.uint, C.uint) {
+	return (*C.struct_rte_ring)(r), C.uintptr_t(uintptr(obj)), C.uint(esize), C.uint(n)
+}
+
+func bytesArgs(r *Ring, obj []byte, esize int) (*C.struct_rte_ring,
+	C.uintptr_t, C.uint, C.uint) {
+	var p unsafe.Pointer
+	if len(obj) >= esize {
+		p = unsafe.Pointer(&obj[0])
+	}
+	return elemArgs(r, p, esize, len(obj)/esize)
+}
+
+// MpEnqueueBulkElem enqueues elements of size esize bytes from obj
+// into the ring. The number of elements is len(obj)/esize. Returns
+// number of enqueued elements (either 0 or all of them) and amount of
+// space in the ring after the enqueue operation has finished.
+//
+// The ring should be created with the same element size.
+func (r *Ring) MpEnqueueBulkElem(obj []byte, esize int) (n, free uint32) {
+	return ret(C.mp_enqueue_bulk_elem(bytesArgs(r, obj, esize)))
+}
+
+// SpEnqueueBulkElem enqueues elements of size esize bytes from obj
+// into the ring. See MpEnqueueBulkElem.
+func (r *Ring) SpEnqueueBulkElem(obj []byte, esize int) (n, free uint32) {
+	return ret(C.sp_enqueue_bulk_elem(bytesArgs(r, obj, esize)))
+}
+
+// EnqueueBulkElem enqueues elements of size esize bytes from obj into
+// the ring. See MpEnqueueBulkElem.
+func (r *Ring) EnqueueBulkElem(obj []byte, esize int) (n, free uint32) {
+	return ret(C.enqueue_bulk_elem(bytesArgs(r, obj, esize)))
+}
+
+// MpEnqueueBurstElem enqueues elements of size esize bytes from obj
+// into the ring. Returns number of enqueued elements and amount of
+// space in the ring after the enqueue operation has finished.
+func (r *Ring) MpEnqueueBurstElem(obj []byte, esize int) (n, free uint32) {
+	return ret(C.mp_enqueue_burst_elem(bytesArgs(r, obj, esize)))
+}
+
+// SpEnqueueBurstElem enqueues elements of size esize bytes from obj
+// into the ring. See MpEnqueueBurstElem.
+func (r *Ring) SpEnqueueBurstElem(obj []byte, esize int) (n, free uint32) {
+	return ret(C.sp_enqueue_burst_elem(bytesArgs(r, obj, esize)))
+}
+
+// EnqueueBurstElem enqueues elements of size esize bytes from obj
+// into the ring. See MpEnqueueBurstElem.
+func (r *Ring) EnqueueBurstElem(obj []byte, esize int) (n, free uint32) {
+	return ret(C.enqueue_burst_elem(bytesArgs(r, obj, esize)))
+}
+
+// McDequeueBulkElem dequeues elements of size esize bytes into obj.
+// The number of elements is len(obj)/esize. Returns number of
+// dequeued elements (either 0 or all of them) and amount of remaining
+// ring entries in the ring after the dequeue operation has finished.
+//
+// The ring should be created with the same element size.
+func (r *Ring) McDequeueBulkElem(obj []byte, esize int) (n, avail uint32) {
+	return ret(C.mc_dequeue_bulk_elem(bytesArgs(r, obj, esize)))
+}
+
+// ScDequeueBulkElem dequeues elements of size esize bytes into obj.
+// See McDequeueBulkElem.
+func (r *Ring) ScDequeueBulkElem(obj []byte, esize int) (n, avail uint32) {
+	return ret(C.sc_dequeue_bulk_elem(bytesArgs(r, obj, esize)))
+}
+
+// DequeueBulkElem dequeues elements of size esize bytes into obj.
+// See McDequeueBulkElem.
+func (r *Ring) DequeueBulkElem(obj []byte, esize int) (n, avail uint32) {
+	return ret(C.dequeue_bulk_elem(bytesArgs(r, obj, esize)))
+}
+
+// McDequeueBurstElem dequeues elements of size esize bytes into obj.
+// Returns number of dequeued elements and amount of remaining ring
+// entries in the ring after the dequeue operation has finished.
+func (r *Ring) McDequeueBurstElem(obj []byte, esize int) (n, avail uint32) {
+	return ret(C.mc_dequeue_burst_elem(bytesArgs(r, obj, esize)))
+}
+
+// ScDequeueBurstElem dequeues elements of size esize bytes into obj.
+// See McDequeueBurstElem.
+func (r *Ring) ScDequeueBurstElem(obj []byte, esize int) (n, avail uint32) {
+	return ret(C.sc_dequeue_burst_elem(bytesArgs(r, obj, esize)))
+}
+
+// DequeueBurstElem dequeues elements of size esize bytes into obj.
+// See McDequeueBurstElem.
+func (r *Ring) DequeueBurstElem(obj []byte, esize int) (n, avail uint32) {
+	return ret(C.dequeue_burst_elem(bytesArgs(r, obj, esize)))
+}
+
+// Elem is the typed view of Ring with elements of type T passed by
+// value. The size of T must be a multiple of 4 and should be equal to
+// the element size the ring was created with. T must not contain Go
+// pointers.
+type Elem[T any] Ring
+
+// elemSize validates T and returns its size.
+func elemSize[T any]() (uint, error) {
+	var v T
+	if sz := unsafe.Sizeof(v); sz == 0 || sz%4 != 0 {
+		return 0, fmt.Errorf("%w: element size %d is not a multiple of 4", syscall.EINVAL, sz)
+	}
+
+	if err := common.CheckNoPointers[T](); err != nil {
+		return 0, err
+	}
+
+	return uint(unsafe.Sizeof(v)), nil
+}
+
+// AsElem returns the typed view of r with elements of type T. It
+// returns syscall.EINVAL if the size of T is not a multiple of 4 or T
+// may contain Go pointers.
+func AsElem[T any](r *Ring) (*Elem[T], error) {
+	if _, err := elemSize[T](); err != nil {
+		return nil, err
+	}
+	return (*Elem[T])(r), nil
+}
+
+// CreateOf creates new ring with elements of type T. See CreateElem
+// and AsElem.
+func CreateOf[T any](name string, count uint, opts ...Option) (*Elem[T], error) {
+	esize, err := elemSize[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := CreateElem(name, esize, count, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return (*Elem[T])(r), nil
+}
+
+// NewOf allocates and initializes ring in Go memory with elements of
+// type T. See NewElem and AsElem.
+func NewOf[T any](name string, count uint, opts ...Option) (*Elem[T], error) {
+	esize, err := elemSize[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := NewElem(name, esize, count, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return (*Elem[T])(r), nil
+}
+
+// Ring returns the untyped ring.
+func (r *Elem[T]) Ring() *Ring {
+	return (*Ring)(r)
+}
+
+func (r *Elem[T]) args(obj []T) (*C.struct_rte_ring, C.uintptr_t, C.uint, C.uint) {
+	var v T
+	var p unsafe.Pointer
+	if len(obj) > 0 {
+		p = unsafe.Pointer(&obj[0])
+	}
+	return elemArgs((*Ring)(r), p, int(unsafe.Sizeof(v)), len(obj))
+}
+
+// Enqueue enqueues a value into the ring.
+func (r *Elem[T]) Enqueue(v T) bool {
+	obj := [1]T{v}
+	n, _ := r.EnqueueBulk(obj[:])
+	return n != 0
+}
+
+// Dequeue dequeues a value from the ring.
+func (r *Elem[T]) Dequeue() (v T, ok bool) {
+	var obj [1]T
+	n, _ := r.DequeueBulk(obj[:])
+	return obj[0], n != 0
+}
+
+// MpEnqueueBulk enqueues given values into the ring. See
+// Ring.MpEnqueueBulkElem.
+func (r *Elem[T]) MpEnqueueBulk(obj []T) (n, free uint32) {
+	return ret(C.mp_enqueue_bulk_elem(r.args(obj)))
+}
+
+// SpEnqueueBulk enqueues given values into the ring. See
+// Ring.SpEnqueueBulkElem.
+func (r *Elem[T]) SpEnqueueBulk(obj []T) (n, free uint32) {
+	return ret(C.sp_enqueue_bulk_elem(r.args(obj)))
+}
+
+// EnqueueBulk enqueues given values into the ring. See
+// Ring.EnqueueBulkElem.
+func (r *Elem[T]) EnqueueBulk(obj []T) (n, free uint32) {
+	return ret(C.enqueue_bulk_elem(r.args(obj)))
+}
+
+// MpEnqueueBurst enqueues given values into the ring. See
+// Ring.MpEnqueueBurstElem.
+func (r *Elem[T]) MpEnqueueBurst(obj []T) (n, free uint32) {
+	return ret(C.mp_enqueue_burst_elem(r.args(obj)))
+}
+
+// SpEnqueueBurst enqueues given values into the ring. See
+// Ring.SpEnqueueBurstElem.
+func (r *Elem[T]) SpEnqueueBurst(obj []T) (n, free uint32) {
+	return ret(C.sp_enqueue_burst_elem(r.args(obj)))
+}
+
+// EnqueueBurst enqueues given values into the ring. See
+// Ring.EnqueueBurstElem.
+func (r *Elem[T]) EnqueueBurst(obj []T) (n, free uint32) {
+	return ret(C.enqueue_burst_elem(r.args(obj)))
+}
+
+// McDequeueBulk dequeues values into given slice. See
+// Ring.McDequeueBulkElem.
+func (r *Elem[T]) McDequeueBulk(obj []T) (n, avail uint32) {
+	return ret(C.mc_dequeue_bulk_elem(r.args(obj)))
+}
+
+// ScDequeueBulk dequeues values into given slice. See
+// Ring.ScDequeueBulkElem.
+func (r *Elem[T]) ScDequeueBulk(obj []T) (n, avail uint32) {
+	return ret(C.sc_dequeue_bulk_elem(r.args(obj)))
+}
+
+// DequeueBulk dequeues values into given slice. See
+// Ring.DequeueBulkElem.
+func (r *Elem[T]) DequeueBulk(obj []T) (n, avail uint32) {
+	return ret(C.dequeue_bulk_elem(r.args(obj)))
+}
+
+// McDequeueBurst dequeues values into given slice. See
+// Ring.McDequeueBurstElem.
+func (r *Elem[T]) McDequeueBurst(obj []T) (n, avail uint32) {
+	return ret(C.mc_dequeue_burst_elem(r.args(obj)))
+}
+
+// ScDequeueBurst dequeues values into given slice. See
+// Ring.ScDequeueBurstElem.
+func (r *Elem[T]) ScDequeueBurst(obj []T) (n, avail uint32) {
+	return ret(C.sc_dequeue_burst_elem(r.args(obj)))
+}
+
+// DequeueBurst dequeues values into given slice. See
+// Ring.DequeueBurstElem.
+func (r *Elem[T]) DequeueBurst(obj []T) (n, avail uint32) {
+	return ret(C.dequeue_burst_elem(r.args(obj)))
+}
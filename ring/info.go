@@ -0,0 +1,113 @@
+package ring
+
+/*
+#include <stdio.h>
+#include <sys/queue.h>
+
+#include <rte_config.h>
+#include <rte_eal_memconfig.h>
+#include <rte_ring.h>
+#include <rte_tailq.h>
+
+static unsigned go_ring_list(struct rte_ring **rings, unsigned n)
+{
+	struct rte_tailq_head *head;
+	struct rte_tailq_entry *te;
+	unsigned i = 0;
+
+	head = rte_eal_tailq_lookup(RTE_TAILQ_RING_NAME);
+	if (head == NULL)
+		return 0;
+
+	rte_mcfg_tailq_read_lock();
+	TAILQ_FOREACH(te, &head->tailq_head, next) {
+		if (i < n)
+			rings[i] = te->data;
+		i++;
+	}
+	rte_mcfg_tailq_read_unlock();
+
+	return i;
+}
+*/
+import "C"
+
+import (
+	"io"
+	"unsafe"
+
+	"github.com/yerden/go-dpdk/common"
+	"github.com/yerden/go-dpdk/memzone"
+)
+
+// RingInfo describes the ring.
+type RingInfo struct {
+	// Name of the ring.
+	Name string
+
+	// Size of the ring, see Size.
+	Size uint
+
+	// Usable capacity of the ring, see Cap.
+	Cap uint
+
+	// Number of entries in the ring.
+	Count uint
+
+	// Flags the ring was created with, e.g. SingleProducer.
+	Flags uint
+
+	// Synchronization modes of producers and consumers.
+	ProdSyncType, ConsSyncType SyncType
+
+	// Memzone of the ring. It is nil unless the ring was created with
+	// Create or CreateElem.
+	Memzone *memzone.Memzone
+}
+
+// Info returns the description of the ring.
+func (r *Ring) Info() RingInfo {
+	return RingInfo{
+		Name:         r.Name(),
+		Size:         r.Size(),
+		Cap:          r.Cap(),
+		Count:        r.Count(),
+		Flags:        uint(r.flags),
+		ProdSyncType: r.ProdSyncType(),
+		ConsSyncType: r.ConsSyncType(),
+		Memzone:      r.Memzone(),
+	}
+}
+
+// Memzone returns the memzone of the ring. It is nil unless the ring
+// was created with Create or CreateElem.
+func (r *Ring) Memzone() *memzone.Memzone {
+	return (*memzone.Memzone)(unsafe.Pointer(r.memzone))
+}
+
+// Dump writes the status of the ring into w.
+func (r *Ring) Dump(w io.Writer) error {
+	return common.DumpTo(w, func(fp unsafe.Pointer) {
+		C.rte_ring_dump((*C.FILE)(fp), (*C.struct_rte_ring)(r))
+	})
+}
+
+// Walk calls fn for each ring in RTE_TAILQ_RING, i.e. those created
+// with Create or CreateElem. The list of rings is taken before the
+// first call of fn so fn may create or free rings. It's up to the
+// caller to make sure the rings are not freed concurrently.
+func Walk(fn func(*Ring)) {
+	var rings []*C.struct_rte_ring
+	for n := 16; ; n *= 2 {
+		rings = make([]*C.struct_rte_ring, n)
+		k := int(C.go_ring_list(&rings[0], C.uint(n)))
+		if k <= n {
+			rings = rings[:k]
+			break
+		}
+	}
+
+	for _, r := range rings {
+		fn((*Ring)(r))
+	}
+}
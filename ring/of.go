@@ -0,0 +1,136 @@
+package ring
+
+/*
+#include <stdlib.h>
+
+#include <rte_config.h>
+#include <rte_ring.h>
+
+#include "ring.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// Of is the typed view of Ring carrying values of type T. T should be
+// pointer-sized, e.g. a pointer to C memory like *mbuf.Mbuf or
+// *mempool.Mempool, or an integer handle like uintptr. The values are
+// stored in the ring as is, so T should not be a pointer to Go memory
+// unless the ring resides in Go memory and is used by Go code only.
+//
+// The methods of Of call the same C functions as the methods of Ring
+// without any conversion or allocation.
+type Of[T any] Ring
+
+// As returns the typed view of r carrying values of type T. It
+// returns syscall.EINVAL if T is not pointer-sized.
+func As[T any](r *Ring) (*Of[T], error) {
+	var v T
+	if sz := unsafe.Sizeof(v); int(sz) != ptrSize {
+		return nil, fmt.Errorf("%w: element size %d is not pointer-sized", syscall.EINVAL, sz)
+	}
+	return (*Of[T])(r), nil
+}
+
+// Ring returns the untyped ring.
+func (r *Of[T]) Ring() *Ring {
+	return (*Ring)(r)
+}
+
+func (r *Of[T]) args(obj []T) (*C.struct_rte_ring, C.uintptr_t, C.uint) {
+	var p uintptr
+	if len(obj) > 0 {
+		p = uintptr(unsafe.Pointer(&obj[0]))
+	}
+	return (*C.struct_rte_ring)(unsafe.Pointer(r)), C.uintptr_t(p), C.uint(len(obj))
+}
+
+// Enqueue enqueues a value into the ring.
+func (r *Of[T]) Enqueue(v T) bool {
+	obj := [1]T{v}
+	n, _ := r.EnqueueBulk(obj[:])
+	return n != 0
+}
+
+// Dequeue dequeues a value from the ring.
+func (r *Of[T]) Dequeue() (v T, ok bool) {
+	var obj [1]T
+	n, _ := r.DequeueBulk(obj[:])
+	return obj[0], n != 0
+}
+
+// MpEnqueueBulk enqueues given values into the ring. See
+// Ring.MpEnqueueBulk.
+func (r *Of[T]) MpEnqueueBulk(obj []T) (n, free uint32) {
+	return ret(C.mp_enqueue_bulk(r.args(obj)))
+}
+
+// SpEnqueueBulk enqueues given values into the ring. See
+// Ring.SpEnqueueBulk.
+func (r *Of[T]) SpEnqueueBulk(obj []T) (n, free uint32) {
+	return ret(C.sp_enqueue_bulk(r.args(obj)))
+}
+
+// EnqueueBulk enqueues given values into the ring. See
+// Ring.EnqueueBulk.
+func (r *Of[T]) EnqueueBulk(obj []T) (n, free uint32) {
+	return ret(C.enqueue_bulk(r.args(obj)))
+}
+
+// MpEnqueueBurst enqueues given values into the ring. See
+// Ring.MpEnqueueBurst.
+func (r *Of[T]) MpEnqueueBurst(obj []T) (n, free uint32) {
+	return ret(C.mp_enqueue_burst(r.args(obj)))
+}
+
+// SpEnqueueBurst enqueues given values into the ring. See
+// Ring.SpEnqueueBurst.
+func (r *Of[T]) SpEnqueueBurst(obj []T) (n, free uint32) {
+	return ret(C.sp_enqueue_burst(r.args(obj)))
+}
+
+// EnqueueBurst enqueues given values into the ring. See
+// Ring.EnqueueBurst.
+func (r *Of[T]) EnqueueBurst(obj []T) (n, free uint32) {
+	return ret(C.enqueue_burst(r.args(obj)))
+}
+
+// McDequeueBulk dequeues values into given slice. See
+// Ring.McDequeueBulk.
+func (r *Of[T]) McDequeueBulk(obj []T) (n, avail uint32) {
+	return ret(C.mc_dequeue_bulk(r.args(obj)))
+}
+
+// ScDequeueBulk dequeues values into given slice. See
+// Ring.ScDequeueBulk.
+func (r *Of[T]) ScDequeueBulk(obj []T) (n, avail uint32) {
+	return ret(C.sc_dequeue_bulk(r.args(obj)))
+}
+
+// DequeueBulk dequeues values into given slice. See
+// Ring.DequeueBulk.
+func (r *Of[T]) DequeueBulk(obj []T) (n, avail uint32) {
+	return ret(C.dequeue_bulk(r.args(obj)))
+}
+
+// McDequeueBurst dequeues values into given slice. See
+// Ring.McDequeueBurst.
+func (r *Of[T]) McDequeueBurst(obj []T) (n, avail uint32) {
+	return ret(C.mc_dequeue_burst(r.args(obj)))
+}
+
+// ScDequeueBurst dequeues values into given slice. See
+// Ring.ScDequeueBurst.
+func (r *Of[T]) ScDequeueBurst(obj []T) (n, avail uint32) {
+	return ret(C.sc_dequeue_burst(r.args(obj)))
+}
+
+// DequeueBurst dequeues values into given slice. See
+// Ring.DequeueBurst.
+func (r *Of[T]) DequeueBurst(obj []T) (n, avail uint32) {
+	return ret(C.dequeue_burst(r.args(obj)))
+}
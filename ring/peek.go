@@ -0,0 +1,270 @@
+package ring
+
+/*
+#include <rte_config.h>
+#include <rte_ring.h>
+#include <rte_ring_elem.h>
+#include <rte_ring_peek.h>
+#include <rte_ring_peek_zc.h>
+
+#include "ring.h"
+
+static void go_enqueue_finish(struct rte_ring *r, uintptr_t objs,
+		unsigned int esize, unsigned int n)
+{
+	rte_ring_enqueue_elem_finish(r, (const void *)objs, esize, n);
+}
+
+static struct compound_int go_dequeue_bulk_start(struct rte_ring *r,
+		uintptr_t objs, unsigned int esize, unsigned int n)
+{
+	struct compound_int out;
+	out.rc = rte_ring_dequeue_bulk_elem_start(r, (void *)objs, esize, n, &out.n);
+	return out;
+}
+
+static struct compound_int go_dequeue_burst_start(struct rte_ring *r,
+		uintptr_t objs, unsigned int esize, unsigned int n)
+{
+	struct compound_int out;
+	out.rc = rte_ring_dequeue_burst_elem_start(r, (void *)objs, esize, n, &out.n);
+	return out;
+}
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+// The peek API allows to split enqueue and dequeue operations into
+// two stages: start and finish. At the start stage the objects are
+// reserved in the ring, at the finish stage the operation is
+// committed, possibly with less objects than reserved, and the ring
+// tail is updated. Between the stages the ring is locked for other
+// producers or consumers respectively, so the caller should finish
+// the operation as soon as possible.
+//
+// The peek API is available only for the rings in single
+// producer/consumer or HTS mode, see SyncST and SyncMTHTS.
+
+const ptrSize = int(unsafe.Sizeof(unsafe.Pointer(nil)))
+
+// EnqueueBulkStart starts to enqueue n objects into the ring. Returns
+// number of reserved slots (either 0 or n) and amount of space in the
+// ring after the enqueue operation has finished. The operation should
+// be completed with EnqueueFinish or EnqueueElemFinish.
+func (r *Ring) EnqueueBulkStart(n uint32) (reserved, free uint32) {
+	var cFree C.uint
+	reserved = uint32(C.rte_ring_enqueue_bulk_start((*C.struct_rte_ring)(r), C.uint(n), &cFree))
+	return reserved, uint32(cFree)
+}
+
+// EnqueueBurstStart starts to enqueue up to n objects into the ring.
+// Returns number of reserved slots and amount of space in the ring
+// after the enqueue operation has finished. The operation should be
+// completed with EnqueueFinish or EnqueueElemFinish.
+func (r *Ring) EnqueueBurstStart(n uint32) (reserved, free uint32) {
+	var cFree C.uint
+	reserved = uint32(C.rte_ring_enqueue_burst_start((*C.struct_rte_ring)(r), C.uint(n), &cFree))
+	return reserved, uint32(cFree)
+}
+
+// EnqueueFinish completes the enqueue operation started with
+// EnqueueBulkStart or EnqueueBurstStart by copying obj into reserved
+// slots. The length of obj should not exceed the number of reserved
+// slots. Empty obj cancels the operation.
+func (r *Ring) EnqueueFinish(obj []unsafe.Pointer) {
+	var p unsafe.Pointer
+	if len(obj) > 0 {
+		p = unsafe.Pointer(&obj[0])
+	}
+	r.enqueueFinish(p, ptrSize, len(obj))
+}
+
+// EnqueueElemFinish completes the enqueue operation started with
+// EnqueueBulkStart or EnqueueBurstStart by copying elements of size
+// esize bytes from obj into reserved slots. The number of elements is
+// len(obj)/esize. See EnqueueFinish.
+func (r *Ring) EnqueueElemFinish(obj []byte, esize int) {
+	var p unsafe.Pointer
+	if len(obj) >= esize {
+		p = unsafe.Pointer(&obj[0])
+	}
+	r.enqueueFinish(p, esize, len(obj)/esize)
+}
+
+func (r *Ring) enqueueFinish(p unsafe.Pointer, esize, n int) {
+	C.go_enqueue_finish(elemArgs(r, p, esize, n))
+}
+
+// DequeueBulkStart starts to dequeue objects into obj. Returns number
+// of dequeued objects (either 0 or all of them) and amount of
+// remaining ring entries in the ring after the dequeue operation has
+// finished. The objects are not removed from the ring until
+// DequeueFinish is called.
+func (r *Ring) DequeueBulkStart(obj []unsafe.Pointer) (n, avail uint32) {
+	return ret(C.go_dequeue_bulk_start(r.ptrArgs(obj)))
+}
+
+// DequeueBurstStart starts to dequeue objects into obj. Returns number
+// of dequeued objects and amount of remaining ring entries in the
+// ring after the dequeue operation has finished. See
+// DequeueBulkStart.
+func (r *Ring) DequeueBurstStart(obj []unsafe.Pointer) (n, avail uint32) {
+	return ret(C.go_dequeue_burst_start(r.ptrArgs(obj)))
+}
+
+// DequeueBulkElemStart starts to dequeue elements of size esize bytes
+// into obj. The number of elements is len(obj)/esize. See
+// DequeueBulkStart.
+func (r *Ring) DequeueBulkElemStart(obj []byte, esize int) (n, avail uint32) {
+	return ret(C.go_dequeue_bulk_start(bytesArgs(r, obj, esize)))
+}
+
+// DequeueBurstElemStart starts to dequeue elements of size esize
+// bytes into obj. The number of elements is len(obj)/esize. See
+// DequeueBurstStart.
+func (r *Ring) DequeueBurstElemStart(obj []byte, esize int) (n, avail uint32) {
+	return ret(C.go_dequeue_burst_start(bytesArgs(r, obj, esize)))
+}
+
+// DequeueFinish completes the dequeue operation started with one of
+// Dequeue*Start methods by removing n objects from the ring. n should
+// not exceed the number of dequeued objects. Zero n cancels the
+// operation.
+func (r *Ring) DequeueFinish(n uint32) {
+	C.rte_ring_dequeue_finish((*C.struct_rte_ring)(r), C.uint(n))
+}
+
+func (r *Ring) ptrArgs(obj []unsafe.Pointer) (*C.struct_rte_ring,
+	C.uintptr_t, C.uint, C.uint) {
+	var p unsafe.Pointer
+	if len(obj) > 0 {
+		p = unsafe.Pointer(&obj[0])
+	}
+	return elemArgs(r, p, ptrSize, len(obj))
+}
+
+// ZCData describes the ring slots reserved by the zero-copy API. The
+// reserved slots may wrap around the end of the ring so they are
+// split into two areas.
+//
+// The zero-copy API allows to read and write objects directly in the
+// ring memory. It has the same restrictions as the peek API.
+type ZCData C.struct_rte_ring_zc_data
+
+// Objs returns the reserved slots of the ring of pointers as two
+// slices. n is the number of reserved slots returned by the start
+// stage of the zero-copy operation. The second slice is nil if the
+// slots don't wrap around.
+func (zcd *ZCData) Objs(n uint32) (s1, s2 []unsafe.Pointer) {
+	n1, n2 := zcd.split(n)
+	s1 = unsafe.Slice((*unsafe.Pointer)(zcd.ptr1), n1)
+	if n2 > 0 {
+		s2 = unsafe.Slice((*unsafe.Pointer)(zcd.ptr2), n2)
+	}
+	return
+}
+
+// Elems returns the reserved slots of the ring with elements of size
+// esize bytes as two byte slices. See Objs.
+func (zcd *ZCData) Elems(n uint32, esize int) (b1, b2 []byte) {
+	n1, n2 := zcd.split(n)
+	b1 = unsafe.Slice((*byte)(zcd.ptr1), n1*esize)
+	if n2 > 0 {
+		b2 = unsafe.Slice((*byte)(zcd.ptr2), n2*esize)
+	}
+	return
+}
+
+func (zcd *ZCData) split(n uint32) (n1, n2 int) {
+	if n1 = int(zcd.n1); n1 > int(n) {
+		n1 = int(n)
+	}
+	return n1, int(n) - n1
+}
+
+func (zcd *ZCData) ptr() *C.struct_rte_ring_zc_data {
+	return (*C.struct_rte_ring_zc_data)(zcd)
+}
+
+// EnqueueZCBulkStart reserves n slots of pointer-sized objects in the
+// ring and fills zcd with their location. Returns number of reserved
+// slots (either 0 or n) and amount of space in the ring after the
+// enqueue operation has finished. The operation should be completed
+// with EnqueueZCFinish.
+func (r *Ring) EnqueueZCBulkStart(n uint32, zcd *ZCData) (reserved, free uint32) {
+	return r.EnqueueZCBulkElemStart(ptrSize, n, zcd)
+}
+
+// EnqueueZCBurstStart reserves up to n slots of pointer-sized objects
+// in the ring. See EnqueueZCBulkStart.
+func (r *Ring) EnqueueZCBurstStart(n uint32, zcd *ZCData) (reserved, free uint32) {
+	return r.EnqueueZCBurstElemStart(ptrSize, n, zcd)
+}
+
+// EnqueueZCBulkElemStart reserves n slots of elements of size esize
+// bytes in the ring. See EnqueueZCBulkStart.
+func (r *Ring) EnqueueZCBulkElemStart(esize int, n uint32, zcd *ZCData) (reserved, free uint32) {
+	var cFree C.uint
+	reserved = uint32(C.rte_ring_enqueue_zc_bulk_elem_start((*C.struct_rte_ring)(r),
+		C.uint(esize), C.uint(n), zcd.ptr(), &cFree))
+	return reserved, uint32(cFree)
+}
+
+// EnqueueZCBurstElemStart reserves up to n slots of elements of size
+// esize bytes in the ring. See EnqueueZCBulkStart.
+func (r *Ring) EnqueueZCBurstElemStart(esize int, n uint32, zcd *ZCData) (reserved, free uint32) {
+	var cFree C.uint
+	reserved = uint32(C.rte_ring_enqueue_zc_burst_elem_start((*C.struct_rte_ring)(r),
+		C.uint(esize), C.uint(n), zcd.ptr(), &cFree))
+	return reserved, uint32(cFree)
+}
+
+// EnqueueZCFinish completes the zero-copy enqueue operation by
+// committing n objects written into the reserved slots. n should not
+// exceed the number of reserved slots. Zero n cancels the operation.
+func (r *Ring) EnqueueZCFinish(n uint32) {
+	C.rte_ring_enqueue_zc_finish((*C.struct_rte_ring)(r), C.uint(n))
+}
+
+// DequeueZCBulkStart locates n pointer-sized objects in the ring and
+// fills zcd with their location. Returns number of located objects
+// (either 0 or n) and amount of remaining ring entries in the ring
+// after the dequeue operation has finished. The operation should be
+// completed with DequeueZCFinish.
+func (r *Ring) DequeueZCBulkStart(n uint32, zcd *ZCData) (located, avail uint32) {
+	return r.DequeueZCBulkElemStart(ptrSize, n, zcd)
+}
+
+// DequeueZCBurstStart locates up to n pointer-sized objects in the
+// ring. See DequeueZCBulkStart.
+func (r *Ring) DequeueZCBurstStart(n uint32, zcd *ZCData) (located, avail uint32) {
+	return r.DequeueZCBurstElemStart(ptrSize, n, zcd)
+}
+
+// DequeueZCBulkElemStart locates n elements of size esize bytes in the
+// ring. See DequeueZCBulkStart.
+func (r *Ring) DequeueZCBulkElemStart(esize int, n uint32, zcd *ZCData) (located, avail uint32) {
+	var cAvail C.uint
+	located = uint32(C.rte_ring_dequeue_zc_bulk_elem_start((*C.struct_rte_ring)(r),
+		C.uint(esize), C.uint(n), zcd.ptr(), &cAvail))
+	return located, uint32(cAvail)
+}
+
+// DequeueZCBurstElemStart locates up to n elements of size esize
+// bytes in the ring. See DequeueZCBulkStart.
+func (r *Ring) DequeueZCBurstElemStart(esize int, n uint32, zcd *ZCData) (located, avail uint32) {
+	var cAvail C.uint
+	located = uint32(C.rte_ring_dequeue_zc_burst_elem_start((*C.struct_rte_ring)(r),
+		C.uint(esize), C.uint(n), zcd.ptr(), &cAvail))
+	return located, uint32(cAvail)
+}
+
+// DequeueZCFinish completes the zero-copy dequeue operation by
+// removing n objects read from the located slots. n should not exceed
+// the number of located objects. Zero n cancels the operation.
+func (r *Ring) DequeueZCFinish(n uint32) {
+	C.rte_ring_dequeue_zc_finish((*C.struct_rte_ring)(r), C.uint(n))
+}
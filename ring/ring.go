@@ -71,6 +71,24 @@ const (
 	// a power-of-2 size is requested, half the ring space will be
 	// wasted.
 	ExactSize = C.RING_F_EXACT_SZ
+	// MpRTSEnqueue specifies that default enqueue operation will
+	// exhibit 'multi-producer RTS mode' behaviour. In relaxed tail
+	// sync (RTS) mode the tail is updated by the last producer
+	// leaving the critical section which makes it less susceptible
+	// to lcore preemption.
+	MpRTSEnqueue = C.RING_F_MP_RTS_ENQ
+	// McRTSDequeue specifies that default dequeue operation will
+	// exhibit 'multi-consumer RTS mode' behaviour.
+	McRTSDequeue = C.RING_F_MC_RTS_DEQ
+	// MpHTSEnqueue specifies that default enqueue operation will
+	// exhibit 'multi-producer HTS mode' behaviour. In head/tail sync
+	// (HTS) mode the head and tail are updated atomically so only
+	// one producer may be in the critical section at a time. This
+	// mode allows the peek API.
+	MpHTSEnqueue = C.RING_F_MP_HTS_ENQ
+	// McHTSDequeue specifies that default dequeue operation will
+	// exhibit 'multi-consumer HTS mode' behaviour.
+	McHTSDequeue = C.RING_F_MC_HTS_DEQ
 )
 
 // Shortcuts for ring creation flags.
@@ -78,6 +96,11 @@ var (
 	OptSC = OptFlag(SingleConsumer)
 	OptSP = OptFlag(SingleProducer)
 	OptES = OptFlag(ExactSize)
+
+	OptMpRTS = OptFlag(MpRTSEnqueue)
+	OptMcRTS = OptFlag(McRTSDequeue)
+	OptMpHTS = OptFlag(MpHTSEnqueue)
+	OptMcHTS = OptFlag(McHTSDequeue)
 )
 
 func err(n ...interface{}) error {
@@ -1,9 +1,14 @@
 package ring_test
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
 	"sync"
 	"syscall"
 	"testing"
+	"time"
 	"unsafe"
 
 	"github.com/yerden/go-dpdk/common"
@@ -146,6 +151,375 @@ func TestRingNewErr(t *testing.T) {
 	assert(r == nil && err == syscall.EINVAL)
 }
 
+func TestRingOf(t *testing.T) {
+	assert := common.Assert(t, true)
+
+	r, err := ring.New("test_ring_of", 64)
+	assert(r != nil && err == nil, err)
+
+	typed, err := ring.As[*int](r)
+	assert(err == nil, err)
+	assert(typed.Ring() == r)
+
+	array := make([]*int, r.Cap())
+	for i := range array {
+		array[i] = new(int)
+	}
+
+	n, free := typed.SpEnqueueBulk(array)
+	assert(n == uint32(len(array)) && free == 0, n, free)
+	assert(!typed.Enqueue(array[0]))
+
+	out := make([]*int, 10)
+	n, avail := typed.ScDequeueBurst(out)
+	assert(n == 10 && avail == uint32(len(array)-10), n, avail)
+	assert(out[9] == array[9])
+
+	n, _ = typed.DequeueBurst(nil)
+	assert(n == 0)
+
+	v, ok := typed.Dequeue()
+	assert(ok && v == array[10])
+
+	_, err = ring.As[[2]uintptr](r)
+	assert(errors.Is(err, syscall.EINVAL), err)
+}
+
+type testElem struct {
+	pid, qid uint16
+	seq      uint32
+	ptr      uintptr
+}
+
+func TestRingElem(t *testing.T) {
+	assert := common.Assert(t, true)
+
+	eal.InitOnceSafe("test", 4)
+
+	err := eal.ExecOnMain(func(ctx *eal.LcoreCtx) {
+		r, err := ring.CreateOf[testElem]("test_ring_elem", 64,
+			ring.OptSC, ring.OptSP, ring.OptSocket(eal.SocketID()))
+		assert(r != nil && err == nil, err)
+		defer r.Ring().Free()
+
+		r1, err := ring.Lookup("test_ring_elem")
+		assert(r.Ring() == r1 && err == nil)
+
+		in := make([]testElem, r.Ring().Cap())
+		for i := range in {
+			in[i] = testElem{pid: 1, qid: uint16(i), seq: uint32(i * 2), ptr: uintptr(i)}
+		}
+
+		n, free := r.SpEnqueueBulk(in)
+		assert(n == uint32(len(in)) && free == 0, n, free)
+		assert(!r.Enqueue(in[0]))
+
+		out := make([]testElem, 10)
+		n, avail := r.ScDequeueBurst(out)
+		assert(n == 10 && avail == uint32(len(in)-10), n, avail)
+		assert(out[9] == in[9], out[9])
+
+		v, ok := r.Dequeue()
+		assert(ok && v == in[10], v)
+	})
+	assert(err == nil, err)
+
+	_, err = ring.GetMemSizeElem(6, 64)
+	assert(err == syscall.EINVAL, err)
+}
+
+func TestRingNewElem(t *testing.T) {
+	assert := common.Assert(t, true)
+
+	const esize = 12
+	r, err := ring.NewElem("test_ring_new_elem", esize, 16)
+	assert(r != nil && err == nil, err)
+
+	in := make([]byte, 3*esize)
+	for i := range in {
+		in[i] = byte(i)
+	}
+
+	n, free := r.EnqueueBulkElem(in, esize)
+	assert(n == 3 && free == 12, n, free)
+
+	n, _ = r.EnqueueBurstElem(in[:esize-1], esize)
+	assert(n == 0, n)
+
+	out := make([]byte, 2*esize)
+	n, avail := r.DequeueBulkElem(out, esize)
+	assert(n == 2 && avail == 1, n, avail)
+	assert(string(out) == string(in[:2*esize]))
+
+	n, avail = r.DequeueBurstElem(out, esize)
+	assert(n == 1 && avail == 0, n, avail)
+	assert(string(out[:esize]) == string(in[2*esize:]))
+
+	_, err = ring.AsElem[[3]byte](r)
+	assert(errors.Is(err, syscall.EINVAL), err)
+	_, err = ring.AsElem[[2]*int](r)
+	assert(errors.Is(err, syscall.EINVAL), err)
+	_, err = ring.NewOf[[2]*int]("test_ring_elem_ptr", 64)
+	assert(errors.Is(err, syscall.EINVAL), err)
+}
+
+func TestRingSyncModes(t *testing.T) {
+	assert := common.Assert(t, true)
+
+	r, err := ring.New("test_ring_rts", 64, ring.OptMpRTS, ring.OptMcHTS)
+	assert(r != nil && err == nil, err)
+	assert(r.ProdSyncType() == ring.SyncMTRTS, r.ProdSyncType())
+	assert(r.ConsSyncType() == ring.SyncMTHTS, r.ConsSyncType())
+
+	assert(r.SetProdHTDMax(8) == nil)
+	assert(r.ProdHTDMax() == 8, r.ProdHTDMax())
+	assert(r.SetConsHTDMax(8) == syscall.ENOTSUP)
+
+	r, err = ring.New("test_ring_st", 64, ring.OptSP, ring.OptSC)
+	assert(r != nil && err == nil, err)
+	assert(r.ProdSyncType() == ring.SyncST, r.ProdSyncType())
+	assert(r.ConsSyncType().String() == "ST", r.ConsSyncType())
+}
+
+func TestRingPeek(t *testing.T) {
+	assert := common.Assert(t, true)
+
+	r, err := ring.New("test_ring_peek", 64, ring.OptMpHTS, ring.OptMcHTS)
+	assert(r != nil && err == nil, err)
+
+	array := make([]int, 8)
+	in := make([]unsafe.Pointer, len(array))
+	for i := range in {
+		in[i] = unsafe.Pointer(&array[i])
+	}
+
+	n, free := r.EnqueueBulkStart(uint32(len(in)))
+	assert(n == uint32(len(in)) && free == 63-8, n, free)
+	r.EnqueueFinish(in[:4])
+	assert(r.Count() == 4, r.Count())
+
+	out := make([]unsafe.Pointer, 4)
+	n, avail := r.DequeueBulkStart(out)
+	assert(n == 4 && avail == 0, n, avail)
+	assert(out[3] == in[3])
+	r.DequeueFinish(0)
+	assert(r.Count() == 4, r.Count())
+
+	n, _ = r.DequeueBurstStart(out[:2])
+	assert(n == 2, n)
+	r.DequeueFinish(n)
+	assert(r.Count() == 2, r.Count())
+}
+
+func TestRingZC(t *testing.T) {
+	assert := common.Assert(t, true)
+
+	r, err := ring.New("test_ring_zc", 8, ring.OptSP, ring.OptSC)
+	assert(r != nil && err == nil, err)
+
+	array := make([]int, 32)
+	var zcd ring.ZCData
+	for i := 0; i < 5; i++ {
+		n, _ := r.EnqueueZCBulkStart(1, &zcd)
+		assert(n == 1, n)
+		s1, s2 := zcd.Objs(n)
+		assert(len(s1) == 1 && s2 == nil)
+		s1[0] = unsafe.Pointer(&array[i])
+		r.EnqueueZCFinish(n)
+	}
+
+	n, _ := r.DequeueZCBurstStart(5, &zcd)
+	assert(n == 5, n)
+	r.DequeueZCFinish(n)
+
+	// reserved slots wrap around the end of the ring
+	n, free := r.EnqueueZCBulkStart(6, &zcd)
+	assert(n == 6 && free == 1, n, free)
+	s1, s2 := zcd.Objs(n)
+	assert(len(s1) == 3 && len(s2) == 3, len(s1), len(s2))
+	for i := range s1 {
+		s1[i] = unsafe.Pointer(&array[10+i])
+	}
+	for i := range s2 {
+		s2[i] = unsafe.Pointer(&array[20+i])
+	}
+	r.EnqueueZCFinish(n)
+
+	out := make([]unsafe.Pointer, 6)
+	n, _ = r.DequeueBulk(out)
+	assert(n == 6, n)
+	assert(out[2] == unsafe.Pointer(&array[12]) && out[3] == unsafe.Pointer(&array[20]), out)
+}
+
+func TestRingChan(t *testing.T) {
+	assert := common.Assert(t, true)
+
+	r, err := ring.New("test_ring_chan", 64)
+	assert(r != nil && err == nil, err)
+
+	notify, err := ring.NewNotifier()
+	assert(err == nil, err)
+	defer notify.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	array := make([]int, 1000)
+	in := r.SendChan(ctx, ring.OptChanSize(16), ring.OptNotify(notify))
+	// no timeout, the wait is interrupted on cancel
+	out := r.RecvChan(ctx, ring.OptBurst(8),
+		ring.OptBackoff(notify.Backoff(-1)))
+
+	go func() {
+		for i := range array {
+			in <- unsafe.Pointer(&array[i])
+		}
+		close(in)
+	}()
+
+	for i := range array {
+		assert(<-out == unsafe.Pointer(&array[i]), i)
+	}
+
+	cancel()
+	for range out {
+	}
+}
+
+func TestRingSendChanDrop(t *testing.T) {
+	assert := common.Assert(t, true)
+
+	r, err := ring.New("test_ring_chan_drop", 4)
+	assert(r != nil && err == nil, err)
+
+	var dropped int
+	done := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	in := r.SendChan(ctx, ring.OptChanSize(16),
+		ring.OptBackoff(ring.BackoffSleep(time.Millisecond, time.Millisecond)),
+		ring.OptDrop(func(objs []unsafe.Pointer) {
+			dropped += len(objs)
+			if dropped == 13 {
+				close(done)
+			}
+		}))
+
+	// ring holds 3 objects, the rest is stuck in the goroutine and
+	// the channel
+	array := make([]int, 16)
+	for i := range array {
+		in <- unsafe.Pointer(&array[i])
+	}
+
+	for !r.IsFull() {
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	<-done
+	assert(r.Count() == 3, r.Count())
+}
+
+func TestNotifierWait(t *testing.T) {
+	assert := common.Assert(t, true)
+
+	notify, err := ring.NewNotifier()
+	assert(err == nil, err)
+	defer notify.Close()
+
+	// sub-millisecond timeout is rounded up
+	start := time.Now()
+	ok, err := notify.Wait(100 * time.Microsecond)
+	assert(!ok && err == nil, ok, err)
+	assert(time.Since(start) >= time.Millisecond, time.Since(start))
+
+	assert(notify.Notify() == nil)
+	ok, err = notify.Wait(-1)
+	assert(ok && err == nil, ok, err)
+}
+
+func TestRingReaderWriter(t *testing.T) {
+	assert := common.Assert(t, true)
+
+	r, err := ring.New("test_ring_rw", 8)
+	assert(r != nil && err == nil, err)
+
+	array := make([]int, 10)
+	objs := make([]unsafe.Pointer, len(array))
+	for i := range objs {
+		objs[i] = unsafe.Pointer(&array[i])
+	}
+
+	ctx := context.Background()
+	w := ring.NewWriter(r, ring.OptBurst(4),
+		ring.OptBackoff(ring.BackoffSleep(time.Millisecond, 4*time.Millisecond)))
+
+	n, err := w.Write(ctx, objs[:3])
+	assert(n == 3 && err == nil && w.Buffered() == 3 && r.IsEmpty(), n, err)
+	assert(w.Flush(ctx) == nil && r.Count() == 3)
+
+	// ring is full
+	tctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	n, err = w.Write(tctx, objs[3:])
+	assert(n == 4 && err == context.DeadlineExceeded, n, err)
+	assert(r.IsFull() && w.Buffered() == 0)
+
+	rd := ring.NewReader(r, ring.OptBurst(4))
+	out := make([]unsafe.Pointer, 2)
+	n, err = rd.Read(ctx, out)
+	assert(n == 2 && err == nil && rd.Buffered() == 2, n, err)
+	assert(out[0] == objs[0] && out[1] == objs[1])
+
+	out = make([]unsafe.Pointer, 8)
+	n, err = rd.Read(ctx, out)
+	assert(n == 2 && err == nil && rd.Buffered() == 0, n, err)
+	n, err = rd.Read(ctx, out)
+	assert(n == 3 && err == nil && r.IsEmpty(), n, err)
+	assert(out[2] == objs[6])
+
+	n, err = rd.Read(tctx, out)
+	assert(n == 0 && err == context.DeadlineExceeded, n, err)
+}
+
+func TestRingInfo(t *testing.T) {
+	assert := common.Assert(t, true)
+
+	eal.InitOnceSafe("test", 4)
+
+	err := eal.ExecOnMain(func(ctx *eal.LcoreCtx) {
+		r, err := ring.Create("test_ring_info", 64, ring.OptSP, ring.OptMcRTS)
+		assert(r != nil && err == nil, err)
+		defer r.Free()
+
+		assert(r.Enqueue(nil))
+		info := r.Info()
+		assert(info.Name == "test_ring_info" && info.Size == 64 &&
+			info.Cap == 63 && info.Count == 1, info)
+		assert(info.Flags&ring.SingleProducer != 0, info.Flags)
+		assert(info.ProdSyncType == ring.SyncST && info.ConsSyncType == ring.SyncMTRTS, info)
+		assert(info.Memzone != nil && info.Memzone == r.Memzone())
+
+		var found bool
+		ring.Walk(func(r1 *ring.Ring) {
+			found = found || r1 == r
+		})
+		assert(found)
+
+		var b bytes.Buffer
+		assert(r.Dump(&b) == nil)
+		assert(strings.Contains(b.String(), "test_ring_info"), b.String())
+	})
+	assert(err == nil, err)
+
+	r, err := ring.New("test_ring_info_go", 64)
+	assert(r != nil && err == nil, err)
+	assert(r.Memzone() == nil)
+	ring.Walk(func(r1 *ring.Ring) {
+		assert(r1 != r)
+	})
+}
+
 func benchmarkRingUintptr(b *testing.B, burst int) {
 	var wg sync.WaitGroup
 	assert := common.Assert(b, true)
@@ -260,3 +634,57 @@ func BenchmarkChanBlockUintptr(b *testing.B) {
 	go receiver(b.N)
 	wg.Wait()
 }
+
+func benchmarkRingOf(b *testing.B, burst int) {
+	var wg sync.WaitGroup
+	assert := common.Assert(b, true)
+
+	eal.InitOnceSafe("test", 4)
+
+	r, err := ring.New("hello", 1024)
+	assert(r != nil && err == nil, err)
+	typed, err := ring.As[uintptr](r)
+	assert(err == nil, err)
+
+	sender := func(n int) {
+		defer wg.Done()
+		i := 0
+		buf := make([]uintptr, burst)
+		for i < n {
+			k, _ := typed.SpEnqueueBulk(buf[:burst])
+			i += int(k)
+		}
+	}
+
+	receiver := func(n int) {
+		defer wg.Done()
+		buf := make([]uintptr, burst)
+		i := 0
+		for i < n {
+			k, _ := typed.ScDequeueBulk(buf)
+			i += int(k)
+		}
+	}
+
+	b.ReportAllocs()
+	wg.Add(2)
+	go sender(b.N)
+	go receiver(b.N)
+	wg.Wait()
+}
+
+func BenchmarkRingOf1(b *testing.B) {
+	benchmarkRingOf(b, 1)
+}
+
+func BenchmarkRingOf20(b *testing.B) {
+	benchmarkRingOf(b, 20)
+}
+
+func BenchmarkRingOf128(b *testing.B) {
+	benchmarkRingOf(b, 128)
+}
+
+func BenchmarkRingOf512(b *testing.B) {
+	benchmarkRingOf(b, 512)
+}
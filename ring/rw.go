@@ -0,0 +1,220 @@
+package ring
+
+import (
+	"context"
+	"unsafe"
+)
+
+// AdapterOption configures Reader, Writer and channel adapters of the
+// ring.
+type AdapterOption struct {
+	f func(*adapterConf)
+}
+
+type adapterConf struct {
+	backoff Backoff
+	burst   int
+	size    int
+	notify  *Notifier
+	drop    func([]unsafe.Pointer)
+}
+
+func makeAdapterConf(opts []AdapterOption) adapterConf {
+	conf := adapterConf{backoff: BackoffYield, burst: 32}
+	for i := range opts {
+		opts[i].f(&conf)
+	}
+
+	if conf.burst <= 0 {
+		conf.burst = 1
+	}
+
+	return conf
+}
+
+// OptBackoff specifies the way to wait for the ring to become
+// non-empty or non-full. Default is BackoffYield.
+func OptBackoff(b Backoff) AdapterOption {
+	return AdapterOption{func(conf *adapterConf) {
+		conf.backoff = b
+	}}
+}
+
+// OptBurst specifies the maximum number of objects enqueued or
+// dequeued at once. Default is 32.
+func OptBurst(n int) AdapterOption {
+	return AdapterOption{func(conf *adapterConf) {
+		conf.burst = n
+	}}
+}
+
+// OptChanSize specifies the buffer size of channels created by
+// RecvChan and SendChan. Default is 0, i.e. the channel is
+// unbuffered.
+func OptChanSize(n int) AdapterOption {
+	return AdapterOption{func(conf *adapterConf) {
+		conf.size = n
+	}}
+}
+
+// OptNotify specifies the Notifier which is notified each time
+// objects are enqueued into the ring.
+func OptNotify(n *Notifier) AdapterOption {
+	return AdapterOption{func(conf *adapterConf) {
+		conf.notify = n
+	}}
+}
+
+// OptDrop specifies the function which is called on objects which
+// were dequeued from the ring but not delivered into the channel, or
+// were sent into the channel but not enqueued into the ring, when the
+// adapter is stopped. It may be used to free the objects, e.g. mbufs.
+func OptDrop(fn func([]unsafe.Pointer)) AdapterOption {
+	return AdapterOption{func(conf *adapterConf) {
+		conf.drop = fn
+	}}
+}
+
+// Reader dequeues objects from the ring in bursts blocking until
+// objects are available. Reader is not safe for concurrent use.
+type Reader struct {
+	r    *Ring
+	conf adapterConf
+	buf  []unsafe.Pointer
+	pos  int
+}
+
+// NewReader creates new Reader of the ring.
+func NewReader(r *Ring, opts ...AdapterOption) *Reader {
+	conf := makeAdapterConf(opts)
+	return &Reader{r: r, conf: conf, buf: make([]unsafe.Pointer, 0, conf.burst)}
+}
+
+// Buffered returns the number of objects dequeued from the ring but
+// not yet read.
+func (rd *Reader) Buffered() int {
+	return len(rd.buf) - rd.pos
+}
+
+// Read reads up to len(objs) objects. It blocks until at least one
+// object is read or ctx is done in which case ctx.Err() is returned.
+// If len(objs) is less than the burst size, the objects are dequeued
+// into the internal buffer and retrieved by subsequent reads.
+func (rd *Reader) Read(ctx context.Context, objs []unsafe.Pointer) (int, error) {
+	if len(objs) == 0 {
+		return 0, nil
+	}
+
+	for attempt := 0; ; {
+		if rd.pos < len(rd.buf) {
+			n := copy(objs, rd.buf[rd.pos:])
+			rd.pos += n
+			return n, nil
+		}
+
+		if len(objs) >= cap(rd.buf) {
+			if n, _ := rd.r.DequeueBurst(objs); n > 0 {
+				return int(n), nil
+			}
+		} else if n, _ := rd.r.DequeueBurst(rd.buf[:cap(rd.buf)]); n > 0 {
+			rd.buf, rd.pos = rd.buf[:n], 0
+			continue
+		}
+
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+
+		attempt++
+		rd.conf.backoff(ctx, attempt)
+	}
+}
+
+// Writer enqueues objects into the ring in bursts blocking until
+// there is enough space in the ring. Writer is not safe for
+// concurrent use.
+type Writer struct {
+	r    *Ring
+	conf adapterConf
+	buf  []unsafe.Pointer
+}
+
+// NewWriter creates new Writer of the ring.
+func NewWriter(r *Ring, opts ...AdapterOption) *Writer {
+	conf := makeAdapterConf(opts)
+	return &Writer{r: r, conf: conf, buf: make([]unsafe.Pointer, 0, conf.burst)}
+}
+
+// Buffered returns the number of objects written but not yet
+// enqueued into the ring.
+func (w *Writer) Buffered() int {
+	return len(w.buf)
+}
+
+// Write writes objs into the internal buffer which is flushed into
+// the ring once it holds a burst of objects. Returns the number of
+// objects written. The error is returned only if ctx is done while
+// waiting for the ring to flush the buffer. Call Flush to enqueue the
+// remaining objects.
+func (w *Writer) Write(ctx context.Context, objs []unsafe.Pointer) (int, error) {
+	var written int
+	for len(objs) > 0 {
+		if len(w.buf) == 0 && len(objs) >= cap(w.buf) {
+			n, err := w.enqueue(ctx, objs)
+			return written + n, err
+		}
+
+		n := copy(w.buf[len(w.buf):cap(w.buf)], objs)
+		w.buf = w.buf[:len(w.buf)+n]
+		objs = objs[n:]
+		written += n
+
+		if len(w.buf) == cap(w.buf) {
+			if err := w.Flush(ctx); err != nil {
+				return written, err
+			}
+		}
+	}
+
+	return written, nil
+}
+
+// Flush enqueues the buffered objects into the ring. It blocks until
+// all objects are enqueued or ctx is done in which case ctx.Err() is
+// returned and the objects which were not enqueued remain buffered.
+func (w *Writer) Flush(ctx context.Context) error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	n, err := w.enqueue(ctx, w.buf)
+	w.buf = w.buf[:copy(w.buf, w.buf[n:])]
+	return err
+}
+
+func (w *Writer) enqueue(ctx context.Context, objs []unsafe.Pointer) (int, error) {
+	var sent int
+	for attempt := 0; sent < len(objs); {
+		if n, _ := w.r.EnqueueBurst(objs[sent:]); n > 0 {
+			sent += int(n)
+			attempt = 0
+			w.notify()
+			continue
+		}
+
+		if err := ctx.Err(); err != nil {
+			return sent, err
+		}
+
+		attempt++
+		w.conf.backoff(ctx, attempt)
+	}
+
+	return sent, nil
+}
+
+func (w *Writer) notify() {
+	if w.conf.notify != nil {
+		_ = w.conf.notify.Notify()
+	}
+}
@@ -0,0 +1,80 @@
+package ring
+
+/*
+#include <rte_config.h>
+#include <rte_ring.h>
+*/
+import "C"
+
+import (
+	"strconv"
+)
+
+// SyncType is the synchronization mode of ring producers or
+// consumers.
+type SyncType int
+
+// Ring synchronization modes.
+const (
+	// SyncMT is multi-thread safe mode (default).
+	SyncMT SyncType = C.RTE_RING_SYNC_MT
+	// SyncST is single thread only mode.
+	SyncST SyncType = C.RTE_RING_SYNC_ST
+	// SyncMTRTS is multi-thread relaxed tail sync mode.
+	SyncMTRTS SyncType = C.RTE_RING_SYNC_MT_RTS
+	// SyncMTHTS is multi-thread head/tail sync mode.
+	SyncMTHTS SyncType = C.RTE_RING_SYNC_MT_HTS
+)
+
+var syncTypeNames = map[SyncType]string{
+	SyncMT:    "MT",
+	SyncST:    "ST",
+	SyncMTRTS: "MT_RTS",
+	SyncMTHTS: "MT_HTS",
+}
+
+func (st SyncType) String() string {
+	if s, ok := syncTypeNames[st]; ok {
+		return s
+	}
+	return "SyncType(" + strconv.Itoa(int(st)) + ")"
+}
+
+// ProdSyncType returns the synchronization mode of ring producers.
+func (r *Ring) ProdSyncType() SyncType {
+	return SyncType(C.rte_ring_get_prod_sync_type((*C.struct_rte_ring)(r)))
+}
+
+// ConsSyncType returns the synchronization mode of ring consumers.
+func (r *Ring) ConsSyncType() SyncType {
+	return SyncType(C.rte_ring_get_cons_sync_type((*C.struct_rte_ring)(r)))
+}
+
+// ProdHTDMax returns the maximum allowed distance between the head
+// and the tail of ring producers. Returns math.MaxUint32 if the
+// producers are not in RTS mode.
+func (r *Ring) ProdHTDMax() uint32 {
+	return uint32(C.rte_ring_get_prod_htd_max((*C.struct_rte_ring)(r)))
+}
+
+// SetProdHTDMax sets the maximum allowed distance between the head
+// and the tail of ring producers. Smaller values reduce the time
+// producers spin waiting for preempted ones at the cost of more
+// frequent tail updates. Returns syscall.ENOTSUP if the producers are
+// not in RTS mode.
+func (r *Ring) SetProdHTDMax(v uint32) error {
+	return err(C.rte_ring_set_prod_htd_max((*C.struct_rte_ring)(r), C.uint32_t(v)))
+}
+
+// ConsHTDMax returns the maximum allowed distance between the head
+// and the tail of ring consumers. Returns math.MaxUint32 if the
+// consumers are not in RTS mode.
+func (r *Ring) ConsHTDMax() uint32 {
+	return uint32(C.rte_ring_get_cons_htd_max((*C.struct_rte_ring)(r)))
+}
+
+// SetConsHTDMax sets the maximum allowed distance between the head
+// and the tail of ring consumers. See SetProdHTDMax.
+func (r *Ring) SetConsHTDMax(v uint32) error {
+	return err(C.rte_ring_set_cons_htd_max((*C.struct_rte_ring)(r), C.uint32_t(v)))
+}
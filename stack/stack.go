@@ -0,0 +1,191 @@
+/*
+Package stack wraps RTE stack library.
+
+Please refer to DPDK Programmer's Guide for reference and caveats.
+*/
+package stack
+
+/*
+#include <stdlib.h>
+
+#include <rte_config.h>
+#include <rte_stack.h>
+
+static unsigned int go_stack_push(struct rte_stack *s, uintptr_t objs,
+		unsigned int n)
+{
+	return rte_stack_push(s, (void * const *)objs, n);
+}
+
+static unsigned int go_stack_pop(struct rte_stack *s, uintptr_t objs,
+		unsigned int n)
+{
+	return rte_stack_pop(s, (void **)objs, n);
+}
+*/
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/yerden/go-dpdk/common"
+)
+
+// Stack is a fixed-size LIFO of pointers. It has the following
+// features:
+//
+// * LIFO (Last In First Out)
+//
+// * Maximum size is fixed; the pointers are stored in a table.
+//
+// * Multi-thread safe push and pop.
+//
+// * Bulk push and pop.
+//
+// The standard implementation is guarded by a spinlock, the lock-free
+// implementation (see LockFree) is a linked list of elements updated
+// with 128-bit compare-and-swap. The lock-free stack is not
+// susceptible to lcore preemption but is available only on x86_64
+// and arm64.
+type Stack C.struct_rte_stack
+
+type stackConf struct {
+	socket C.int
+	flags  C.uint32_t
+}
+
+// Option alters stack behaviour.
+type Option struct {
+	f func(*stackConf)
+}
+
+const (
+	// LockFree specifies that the stack is lock-free.
+	LockFree uint = C.RTE_STACK_F_LF
+)
+
+// OptLF is the shortcut for OptFlag(LockFree).
+var OptLF = OptFlag(LockFree)
+
+// OptSocket specifies the socket id where the memzone would be
+// created.
+func OptSocket(socket uint) Option {
+	return Option{func(sc *stackConf) {
+		sc.socket = C.int(socket)
+	}}
+}
+
+// OptFlag adds one of permitted flags for the stack creation.
+func OptFlag(flag uint) Option {
+	return Option{func(sc *stackConf) {
+		sc.flags |= C.uint32_t(flag)
+	}}
+}
+
+func err(n ...interface{}) error {
+	if len(n) == 0 {
+		return common.RteErrno()
+	}
+
+	return common.IntToErr(n[0])
+}
+
+// Create creates new stack named name in memory. count is the
+// maximum number of objects the stack can hold, it is not required to
+// be a power of two.
+//
+// This function uses rte_memzone_reserve() to allocate memory. The
+// stack is added in RTE_TAILQ_STACK list.
+//
+// Returns syscall.ENOTSUP if the lock-free stack is requested but not
+// supported on the current platform.
+func Create(name string, count uint, opts ...Option) (*Stack, error) {
+	sc := &stackConf{socket: C.SOCKET_ID_ANY}
+	for i := range opts {
+		opts[i].f(sc)
+	}
+
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+
+	s := (*Stack)(C.rte_stack_create(cname, C.uint(count), sc.socket, sc.flags))
+	if s == nil {
+		return nil, err()
+	}
+	return s, nil
+}
+
+// Lookup searches a stack from its name in RTE_TAILQ_STACK.
+func Lookup(name string) (*Stack, error) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	s := (*Stack)(C.rte_stack_lookup(cname))
+	if s == nil {
+		return nil, err()
+	}
+	return s, nil
+}
+
+// Free deallocates all memory used by the stack.
+func (s *Stack) Free() {
+	C.rte_stack_free((*C.struct_rte_stack)(s))
+}
+
+// Name returns stack's name stored when creating.
+func (s *Stack) Name() string {
+	return C.GoString(&(*C.struct_rte_stack)(s).name[0])
+}
+
+// Cap returns the number of elements which can be stored in the
+// stack.
+func (s *Stack) Cap() uint {
+	return uint((*C.struct_rte_stack)(s).capacity)
+}
+
+// Count returns the number of objects in the stack.
+func (s *Stack) Count() uint {
+	return uint(C.rte_stack_count((*C.struct_rte_stack)(s)))
+}
+
+// FreeCount returns the number of free entries in the stack.
+func (s *Stack) FreeCount() uint {
+	return uint(C.rte_stack_free_count((*C.struct_rte_stack)(s)))
+}
+
+// IsEmpty tests if the stack is empty.
+func (s *Stack) IsEmpty() bool {
+	return C.rte_stack_is_empty((*C.struct_rte_stack)(s)) != 0
+}
+
+func args(s *Stack, obj []unsafe.Pointer) (*C.struct_rte_stack, C.uintptr_t, C.uint) {
+	var p unsafe.Pointer
+	if len(obj) > 0 {
+		p = unsafe.Pointer(&obj[0])
+	}
+	return (*C.struct_rte_stack)(s), C.uintptr_t(uintptr(p)), C.uint(len(obj))
+}
+
+// Push pushes an object onto the stack.
+func (s *Stack) Push(obj unsafe.Pointer) bool {
+	return s.PushBulk([]unsafe.Pointer{obj}) != 0
+}
+
+// PushBulk pushes objects onto the stack. Returns number of pushed
+// objects, either 0 or all of them.
+func (s *Stack) PushBulk(obj []unsafe.Pointer) uint32 {
+	return uint32(C.go_stack_push(args(s, obj)))
+}
+
+// Pop pops an object from the stack.
+func (s *Stack) Pop() (unsafe.Pointer, bool) {
+	objs := []unsafe.Pointer{nil}
+	n := s.PopBulk(objs)
+	return objs[0], n != 0
+}
+
+// PopBulk pops objects from the stack into obj. The last pushed
+// object is stored first. Returns number of popped objects, either 0
+// or all of them.
+func (s *Stack) PopBulk(obj []unsafe.Pointer) uint32 {
+	return uint32(C.go_stack_pop(args(s, obj)))
+}
@@ -0,0 +1,69 @@
+package stack_test
+
+import (
+	"syscall"
+	"testing"
+	"unsafe"
+
+	"github.com/yerden/go-dpdk/common"
+	"github.com/yerden/go-dpdk/eal"
+	"github.com/yerden/go-dpdk/stack"
+)
+
+func testStack(t *testing.T, name string, opts ...stack.Option) {
+	assert := common.Assert(t, true)
+
+	s, err := stack.Create(name, 100, append(opts, stack.OptSocket(eal.SocketID()))...)
+	if err == syscall.ENOTSUP {
+		t.Log("stack is not supported:", name)
+		return
+	}
+	assert(s != nil && err == nil, err)
+	defer s.Free()
+
+	s1, err := stack.Lookup(name)
+	assert(s == s1 && err == nil, err)
+	assert(s.Name() == name, s.Name())
+	assert(s.Cap() == 100 && s.IsEmpty(), s.Cap())
+
+	array := make([]int, 100)
+	objs := make([]unsafe.Pointer, len(array))
+	for i := range objs {
+		objs[i] = unsafe.Pointer(&array[i])
+	}
+
+	n := s.PushBulk(objs[:99])
+	assert(n == 99 && s.Count() == 99 && s.FreeCount() == 1, n, s.Count())
+	assert(s.PushBulk(objs[:2]) == 0)
+	assert(s.Push(objs[99]) && !s.Push(objs[0]))
+
+	obj, ok := s.Pop()
+	assert(ok && obj == objs[99])
+
+	out := make([]unsafe.Pointer, 10)
+	n = s.PopBulk(out)
+	assert(n == 10 && s.Count() == 89, n, s.Count())
+	assert(out[0] == objs[98] && out[9] == objs[89])
+
+	out = make([]unsafe.Pointer, 90)
+	assert(s.PopBulk(out) == 0 && s.Count() == 89)
+	assert(s.PopBulk(out[:89]) == 89 && s.IsEmpty())
+
+	_, ok = s.Pop()
+	assert(!ok)
+}
+
+func TestStack(t *testing.T) {
+	assert := common.Assert(t, true)
+
+	eal.InitOnceSafe("test", 4)
+
+	err := eal.ExecOnMain(func(ctx *eal.LcoreCtx) {
+		testStack(t, "test_stack")
+		testStack(t, "test_stack_lf", stack.OptLF)
+
+		_, err := stack.Lookup("test_stack_nonexistent")
+		assert(err != nil)
+	})
+	assert(err == nil, err)
+}